@@ -0,0 +1,461 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"testserver/templates"
+)
+
+const (
+	activityContentType = "application/activity+json"
+	activityStreamsNS   = "https://www.w3.org/ns/activitystreams"
+	activityPublic      = activityStreamsNS + "#Public"
+	maxActivitySize     = 1 << 20
+)
+
+// A remote actor following the profile
+type apFollower struct {
+	ID    string `json:"id"`
+	Inbox string `json:"inbox"`
+}
+
+// Persisted between runs so followers and announcements survive restarts
+type apState struct {
+	Followers []apFollower `json:"followers"`
+	Announced []string     `json:"announced"` // IDs of notes already delivered to followers
+}
+
+// activityPub serves a read-only ActivityPub actor for the profile. It accepts
+// follows, publishes project announcements and signs every delivery.
+type activityPub struct {
+	user      string
+	base      string // Public base URL, without trailing slash
+	key       *rsa.PrivateKey
+	statePath string
+	client    *http.Client
+
+	mu    sync.Mutex
+	state apState
+}
+
+func newActivityPub(user, base, keyPath, statePath string) (*activityPub, error) {
+	key, err := loadOrCreateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	ap := &activityPub{
+		user:      user,
+		base:      strings.TrimSuffix(base, "/"),
+		key:       key,
+		statePath: statePath,
+		client:    newPublicClient(10 * time.Second),
+	}
+	content, err := os.ReadFile(statePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(content, &ap.state); err != nil {
+			return nil, fmt.Errorf("%s: %w", statePath, err)
+		}
+	}
+	return ap, nil
+}
+
+// loadOrCreateKey reads the actor's RSA key, generating it on first run
+func loadOrCreateKey(path string) (*rsa.PrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		block, _ := pem.Decode(content)
+		if block == nil {
+			return nil, fmt.Errorf("%s: no PEM block", path)
+		}
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, err
+	}
+	log.Printf("Generated ActivityPub key %s", path)
+	return key, nil
+}
+
+func (ap *activityPub) actorID() string { return ap.base + "/ap/actor" }
+func (ap *activityPub) keyID() string   { return ap.actorID() + "#main-key" }
+
+// Routes mounts WebFinger and the ActivityPub endpoints on router
+func (ap *activityPub) Routes(router chi.Router) {
+	router.Get("/.well-known/webfinger", ap.serveWebFinger)
+	router.Get("/ap/actor", ap.serveActor)
+	router.Get("/ap/outbox", ap.serveOutbox)
+	router.Get("/ap/followers", ap.serveFollowers)
+	router.Get("/ap/notes/{slug}", ap.serveNote)
+	router.Post("/ap/inbox", ap.serveInbox)
+}
+
+func writeActivity(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", activityContentType)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (ap *activityPub) serveWebFinger(w http.ResponseWriter, r *http.Request) {
+	host := strings.TrimPrefix(strings.TrimPrefix(ap.base, "https://"), "http://")
+	resource := r.URL.Query().Get("resource")
+	if resource != "acct:"+ap.user+"@"+host && resource != ap.actorID() {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/jrd+json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	json.NewEncoder(w).Encode(map[string]any{
+		"subject": "acct:" + ap.user + "@" + host,
+		"aliases": []string{ap.actorID(), ap.base + "/"},
+		"links": []map[string]string{
+			{"rel": "self", "type": activityContentType, "href": ap.actorID()},
+			{"rel": "http://webfinger.net/rel/profile-page", "type": "text/html", "href": ap.base + "/"},
+		},
+	})
+}
+
+func (ap *activityPub) serveActor(w http.ResponseWriter, r *http.Request) {
+	lang := detectLanguage(r)
+	profile := loadProfileData(lang)
+	publicKey, err := encodePublicKey(&ap.key.PublicKey)
+	if err != nil {
+		http.Error(w, "Error encoding key", http.StatusInternalServerError)
+		return
+	}
+	attachments := []map[string]string{}
+	for _, link := range profile.Links {
+		attachments = append(attachments, map[string]string{
+			"type":  "PropertyValue",
+			"name":  link.Name,
+			"value": fmt.Sprintf(`<a href="%s" rel="me nofollow noopener" target="_blank">%s</a>`, html.EscapeString(link.URL), html.EscapeString(link.URL)),
+		})
+	}
+	writeActivity(w, map[string]any{
+		"@context":                  []string{activityStreamsNS, "https://w3id.org/security/v1"},
+		"id":                        ap.actorID(),
+		"type":                      "Person",
+		"preferredUsername":         ap.user,
+		"name":                      profile.Name,
+		"summary":                   "<p>" + html.EscapeString(templates.PlainText(profile.Text)) + "</p>",
+		"url":                       ap.base + "/?lang=" + lang,
+		"inbox":                     ap.base + "/ap/inbox",
+		"outbox":                    ap.base + "/ap/outbox",
+		"followers":                 ap.base + "/ap/followers",
+		"manuallyApprovesFollowers": false,
+		"discoverable":              true,
+		"icon": map[string]string{
+			"type":      "Image",
+			"mediaType": "image/png",
+			"url":       ap.base + templates.Asset("icon-512.png"),
+		},
+		"publicKey": map[string]string{
+			"id":           ap.keyID(),
+			"owner":        ap.actorID(),
+			"publicKeyPem": publicKey,
+		},
+		"attachment": attachments,
+	})
+}
+
+// projectNote announces a project, with its text in every supported language
+func (ap *activityPub) projectNote(slug string) (map[string]any, bool) {
+	contentMap := map[string]string{}
+	for _, lang := range templates.Languages {
+		for _, item := range loadProjectsData(lang).ProjectItems {
+			if item.Slug == slug {
+				contentMap[lang] = fmt.Sprintf(`<p><strong>%s</strong></p><p>%s</p><p><a href="%s">%s</a></p>`,
+					html.EscapeString(item.Title), html.EscapeString(templates.PlainText(item.Description)),
+					html.EscapeString(item.GitHubLink), html.EscapeString(item.GitHubLink))
+			}
+		}
+	}
+	if len(contentMap) == 0 {
+		return nil, false
+	}
+	published := contentLastMod(append(contentFiles(embeddedFS, "projects", "en"), contentFiles(embeddedFS, "projects", "fr")...))
+	return map[string]any{
+		"id":           ap.base + "/ap/notes/" + slug,
+		"type":         "Note",
+		"attributedTo": ap.actorID(),
+		"content":      contentMap["en"],
+		"contentMap":   contentMap,
+		"url":          ap.base + "/#projects",
+		"published":    published.UTC().Format(time.RFC3339),
+		"to":           []string{activityPublic},
+		"cc":           []string{ap.base + "/ap/followers"},
+	}, true
+}
+
+func (ap *activityPub) createActivity(note map[string]any) map[string]any {
+	return map[string]any{
+		"@context":  activityStreamsNS,
+		"id":        note["id"].(string) + "/activity",
+		"type":      "Create",
+		"actor":     ap.actorID(),
+		"published": note["published"],
+		"to":        note["to"],
+		"cc":        note["cc"],
+		"object":    note,
+	}
+}
+
+func (ap *activityPub) serveNote(w http.ResponseWriter, r *http.Request) {
+	note, ok := ap.projectNote(chi.URLParam(r, "slug"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	note["@context"] = activityStreamsNS
+	writeActivity(w, note)
+}
+
+func (ap *activityPub) serveOutbox(w http.ResponseWriter, r *http.Request) {
+	items := []map[string]any{}
+	for _, item := range loadProjectsData("en").ProjectItems {
+		if note, ok := ap.projectNote(item.Slug); ok {
+			items = append(items, ap.createActivity(note))
+		}
+	}
+	writeActivity(w, map[string]any{
+		"@context":     activityStreamsNS,
+		"id":           ap.base + "/ap/outbox",
+		"type":         "OrderedCollection",
+		"totalItems":   len(items),
+		"orderedItems": items,
+	})
+}
+
+func (ap *activityPub) serveFollowers(w http.ResponseWriter, r *http.Request) {
+	ap.mu.Lock()
+	total := len(ap.state.Followers)
+	ap.mu.Unlock()
+	// Follower identities are not published
+	writeActivity(w, map[string]any{
+		"@context":   activityStreamsNS,
+		"id":         ap.base + "/ap/followers",
+		"type":       "OrderedCollection",
+		"totalItems": total,
+	})
+}
+
+// fetchActor retrieves a remote actor document with a signed GET; the client refuses
+// loopback and private addresses
+func (ap *activityPub) fetchActor(ctx context.Context, id string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", activityContentType)
+	if err := signRequest(req, nil, ap.keyID(), ap.key); err != nil {
+		return nil, err
+	}
+	resp, err := ap.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", id, resp.Status)
+	}
+	var actor map[string]any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxActivitySize)).Decode(&actor); err != nil {
+		return nil, err
+	}
+	return actor, nil
+}
+
+// actorKey resolves a keyId to the owning actor and its public key. The actor is the
+// document at the keyId without its fragment, and must identify itself by that URL,
+// so a key can't vouch for an actor on another host.
+func (ap *activityPub) actorKey(ctx context.Context, keyID string) (map[string]any, *rsa.PublicKey, error) {
+	actorURL, _, _ := strings.Cut(keyID, "#")
+	actor, err := ap.fetchActor(ctx, actorURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if id, _ := actor["id"].(string); id != actorURL {
+		return nil, nil, fmt.Errorf("key %s belongs to %s, not %q", keyID, actorURL, id)
+	}
+	publicKey, _ := actor["publicKey"].(map[string]any)
+	if id, _ := publicKey["id"].(string); id != keyID {
+		return nil, nil, fmt.Errorf("actor %s does not publish key %s", actorURL, keyID)
+	}
+	if owner, _ := publicKey["owner"].(string); owner != "" && owner != actorURL {
+		return nil, nil, fmt.Errorf("key %s is owned by %s, not %s", keyID, owner, actorURL)
+	}
+	pemKey, _ := publicKey["publicKeyPem"].(string)
+	key, err := decodePublicKey(pemKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return actor, key, nil
+}
+
+func (ap *activityPub) serveInbox(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxActivitySize))
+	if err != nil {
+		http.Error(w, "Error reading body", http.StatusBadRequest)
+		return
+	}
+	var signer map[string]any
+	_, err = verifyRequest(r, body, func(keyID string) (*rsa.PublicKey, error) {
+		actor, key, err := ap.actorKey(r.Context(), keyID)
+		signer = actor
+		return key, err
+	})
+	if err != nil {
+		log.Printf("Rejected inbox delivery: %v", err)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var activity map[string]any
+	if err := json.Unmarshal(body, &activity); err != nil {
+		http.Error(w, "Invalid activity", http.StatusBadRequest)
+		return
+	}
+	actorID, _ := activity["actor"].(string)
+	if signerID, _ := signer["id"].(string); actorID == "" || actorID != signerID {
+		http.Error(w, "Actor does not match signature", http.StatusForbidden)
+		return
+	}
+
+	switch activity["type"] {
+	case "Follow":
+		if object, _ := activity["object"].(string); object != ap.actorID() {
+			http.Error(w, "Unknown object", http.StatusBadRequest)
+			return
+		}
+		inbox, _ := signer["inbox"].(string)
+		if inbox == "" {
+			http.Error(w, "Actor has no inbox", http.StatusBadRequest)
+			return
+		}
+		ap.addFollower(apFollower{ID: actorID, Inbox: inbox})
+		accept := map[string]any{
+			"@context": activityStreamsNS,
+			"id":       fmt.Sprintf("%s/ap/accepts/%d", ap.base, time.Now().UnixNano()),
+			"type":     "Accept",
+			"actor":    ap.actorID(),
+			"object":   activity,
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := ap.deliver(ctx, inbox, accept); err != nil {
+				log.Printf("Error accepting follow from %s: %v", actorID, err)
+			}
+		}()
+	case "Undo":
+		if object, _ := activity["object"].(map[string]any); object["type"] == "Follow" {
+			ap.removeFollower(actorID)
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (ap *activityPub) addFollower(follower apFollower) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	ap.state.Followers = slices.DeleteFunc(ap.state.Followers, func(f apFollower) bool { return f.ID == follower.ID })
+	ap.state.Followers = append(ap.state.Followers, follower)
+	ap.saveState()
+}
+
+func (ap *activityPub) removeFollower(id string) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	ap.state.Followers = slices.DeleteFunc(ap.state.Followers, func(f apFollower) bool { return f.ID == id })
+	ap.saveState()
+}
+
+// saveState writes the state file; callers hold mu
+func (ap *activityPub) saveState() {
+	content, err := json.MarshalIndent(ap.state, "", "\t")
+	if err == nil {
+		err = os.WriteFile(ap.statePath, content, 0600)
+	}
+	if err != nil {
+		log.Printf("Error saving ActivityPub state: %v", err)
+	}
+}
+
+// deliver POSTs a signed activity to inbox
+func (ap *activityPub) deliver(ctx context.Context, inbox string, activity any) error {
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", activityContentType)
+	if err := signRequest(req, body, ap.keyID(), ap.key); err != nil {
+		return err
+	}
+	resp, err := ap.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("delivering to %s: %s", inbox, resp.Status)
+	}
+	return nil
+}
+
+// AnnounceNewProjects delivers a Create for every project not yet announced to each follower
+func (ap *activityPub) AnnounceNewProjects(ctx context.Context) {
+	ap.mu.Lock()
+	followers := slices.Clone(ap.state.Followers)
+	announced := slices.Clone(ap.state.Announced)
+	ap.mu.Unlock()
+
+	for _, item := range loadProjectsData("en").ProjectItems {
+		note, ok := ap.projectNote(item.Slug)
+		if !ok || slices.Contains(announced, note["id"].(string)) {
+			continue
+		}
+		activity := ap.createActivity(note)
+		for _, follower := range followers {
+			if err := ap.deliver(ctx, follower.Inbox, activity); err != nil {
+				log.Printf("Error announcing %s to %s: %v", item.Slug, follower.ID, err)
+			}
+		}
+		ap.mu.Lock()
+		ap.state.Announced = append(ap.state.Announced, note["id"].(string))
+		ap.saveState()
+		ap.mu.Unlock()
+	}
+}
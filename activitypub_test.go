@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// remoteActor stands in for another fediverse server: it publishes an actor document
+// and records the activities delivered to its inbox
+type remoteActor struct {
+	server     *httptest.Server
+	key        *rsa.PrivateKey
+	documents  map[string]map[string]any // Actor documents by path
+	deliveries chan map[string]any
+	senderKey  *rsa.PublicKey // Key deliveries must be signed with
+}
+
+func newRemoteActor(t *testing.T, senderKey *rsa.PublicKey) *remoteActor {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := &remoteActor{key: key, documents: map[string]map[string]any{}, deliveries: make(chan map[string]any, 1), senderKey: senderKey}
+	remote.server = httptest.NewServer(http.HandlerFunc(remote.serveHTTP))
+	t.Cleanup(remote.server.Close)
+	return remote
+}
+
+func (remote *remoteActor) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/inbox" {
+		body, _ := io.ReadAll(r.Body)
+		if _, err := verifyRequest(r, body, func(string) (*rsa.PublicKey, error) { return remote.senderKey, nil }); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var activity map[string]any
+		json.Unmarshal(body, &activity)
+		remote.deliveries <- activity
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	doc, ok := remote.documents[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeActivity(w, doc)
+}
+
+// publish serves an actor document at path claiming id, with the remote key
+func (remote *remoteActor) publish(t *testing.T, path, id string) string {
+	t.Helper()
+	publicKey, err := encodePublicKey(&remote.key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID := remote.server.URL + path + "#main-key"
+	remote.documents[path] = map[string]any{
+		"id":        id,
+		"type":      "Person",
+		"inbox":     remote.server.URL + "/inbox",
+		"publicKey": map[string]any{"id": keyID, "owner": id, "publicKeyPem": publicKey},
+	}
+	return keyID
+}
+
+func newTestActivityPub(t *testing.T) *activityPub {
+	t.Helper()
+	dir := t.TempDir()
+	ap, err := newActivityPub("ada", "https://cv.example", filepath.Join(dir, "key.pem"), filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ap
+}
+
+// postToInbox delivers activity to ap's inbox, signed with key as keyID
+func postToInbox(t *testing.T, ap *activityPub, activity map[string]any, keyID string, key *rsa.PrivateKey) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(activity)
+	req := httptest.NewRequest(http.MethodPost, "https://cv.example/ap/inbox", bytes.NewReader(body))
+	req.Header.Set("Content-Type", activityContentType)
+	if err := signRequest(req, body, keyID, key); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	ap.serveInbox(rec, req)
+	return rec
+}
+
+func TestInboxFollow(t *testing.T) {
+	ap := newTestActivityPub(t)
+	remote := newRemoteActor(t, &ap.key.PublicKey)
+	ap.client = remote.server.Client() // The stand-in listens on loopback
+	actorID := remote.server.URL + "/actor"
+	keyID := remote.publish(t, "/actor", actorID)
+
+	follow := map[string]any{"id": actorID + "/follows/1", "type": "Follow", "actor": actorID, "object": ap.actorID()}
+	if rec := postToInbox(t, ap, follow, keyID, remote.key); rec.Code != http.StatusAccepted {
+		t.Fatalf("Follow got %d: %s", rec.Code, rec.Body)
+	}
+
+	select {
+	case accept := <-remote.deliveries:
+		object, _ := accept["object"].(map[string]any)
+		if accept["type"] != "Accept" || accept["actor"] != ap.actorID() || object["id"] != follow["id"] {
+			t.Errorf("delivered %v, want an Accept of the follow", accept)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no Accept delivered to the follower's inbox")
+	}
+	ap.mu.Lock()
+	followers := ap.state.Followers
+	ap.mu.Unlock()
+	if len(followers) != 1 || followers[0].ID != actorID || followers[0].Inbox != remote.server.URL+"/inbox" {
+		t.Errorf("followers = %v", followers)
+	}
+
+	undo := map[string]any{"type": "Undo", "actor": actorID, "object": follow}
+	if rec := postToInbox(t, ap, undo, keyID, remote.key); rec.Code != http.StatusAccepted {
+		t.Fatalf("Undo got %d: %s", rec.Code, rec.Body)
+	}
+	if len(ap.state.Followers) != 0 {
+		t.Errorf("followers after Undo = %v", ap.state.Followers)
+	}
+}
+
+func TestInboxRejectsForeignActor(t *testing.T) {
+	ap := newTestActivityPub(t)
+	remote := newRemoteActor(t, &ap.key.PublicKey)
+	ap.client = remote.server.Client()
+	victim := "https://victim.example/users/alice"
+
+	// A key document that claims to be another host's actor
+	keyID := remote.publish(t, "/evil", victim)
+	follow := map[string]any{"type": "Follow", "actor": victim, "object": ap.actorID()}
+	if rec := postToInbox(t, ap, follow, keyID, remote.key); rec.Code != http.StatusUnauthorized {
+		t.Errorf("impersonating Follow got %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	// A genuine key signing for another actor
+	actorID := remote.server.URL + "/actor"
+	keyID = remote.publish(t, "/actor", actorID)
+	if rec := postToInbox(t, ap, follow, keyID, remote.key); rec.Code != http.StatusForbidden {
+		t.Errorf("Follow for another actor got %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if len(ap.state.Followers) != 0 {
+		t.Errorf("followers = %v", ap.state.Followers)
+	}
+}
+
+func TestFetchActorRefusesPrivateAddresses(t *testing.T) {
+	ap := newTestActivityPub(t)
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer local.Close()
+	_, err := ap.fetchActor(t.Context(), local.URL+"/actor")
+	if err == nil || !strings.Contains(err.Error(), "non-public address") {
+		t.Errorf("fetching a loopback actor: %v, want a refusal", err)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":        true,
+		"2606:2800:220:1::":    true,
+		"127.0.0.1":            false,
+		"::1":                  false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"fc00::1":              false,
+		"fe80::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:93.184.216.34": true,
+	} {
+		if got := publicAddress(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("publicAddress(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
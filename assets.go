@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"testserver/templates"
+)
+
+// staticAsset is a file from static/ held in memory with its precompressed variants
+type staticAsset struct {
+	name        string // Logical name, e.g. styles.css
+	hashedName  string // Fingerprinted name, e.g. styles.3f2a9c1b0d.css
+	hash        string
+	contentType string
+	content     []byte
+	gzip        []byte
+	brotli      []byte
+}
+
+type assetStore struct {
+	byName  map[string]*staticAsset // Logical and fingerprinted names
+	modTime time.Time
+}
+
+// Only text formats benefit from precompression
+var compressibleExtensions = map[string]bool{
+	".css": true, ".js": true, ".json": true, ".svg": true, ".html": true, ".txt": true, ".xml": true,
+}
+
+// loadAssets hashes and precompresses every file under dir in fsys
+func loadAssets(fsys fs.FS, dir string) (*assetStore, error) {
+	store := &assetStore{
+		byName:  map[string]*staticAsset{},
+		modTime: time.Now(),
+	}
+	err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		store.add(strings.TrimPrefix(p, dir+"/"), content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// add fingerprints content under name and precompresses it when worthwhile
+func (s *assetStore) add(name string, content []byte) {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])[:10]
+	ext := path.Ext(name)
+	asset := &staticAsset{
+		name:        name,
+		hashedName:  strings.TrimSuffix(name, ext) + "." + hash + ext,
+		hash:        hash,
+		contentType: mime.TypeByExtension(ext),
+		content:     content,
+	}
+	if asset.contentType == "" {
+		asset.contentType = http.DetectContentType(content)
+	}
+	if compressibleExtensions[ext] {
+		asset.gzip = gzipBytes(content)
+		asset.brotli = brotliBytes(content)
+	}
+	s.byName[asset.name] = asset
+	s.byName[asset.hashedName] = asset
+}
+
+func gzipBytes(content []byte) []byte {
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	zw.Write(content)
+	zw.Close()
+	if buf.Len() >= len(content) {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func brotliBytes(content []byte) []byte {
+	var buf bytes.Buffer
+	bw := brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	bw.Write(content)
+	bw.Close()
+	if buf.Len() >= len(content) {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// Paths maps logical asset names to their fingerprinted URLs for the templates
+func (s *assetStore) Paths() map[string]string {
+	paths := map[string]string{}
+	for name, asset := range s.byName {
+		if name == asset.name {
+			paths[name] = "/static/" + asset.hashedName
+		}
+	}
+	return paths
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header allows coding
+func acceptsEncoding(header, coding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), coding) {
+			continue
+		}
+		qvalue, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !ok {
+			return true
+		}
+		q, err := strconv.ParseFloat(qvalue, 64)
+		return err == nil && q > 0
+	}
+	return false
+}
+
+// ServeHTTP serves /static/* with content negotiation and cache headers.
+// middleware.Compress leaves responses alone once Content-Encoding is set.
+func (s *assetStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/static/")
+	asset, ok := s.byName[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	content, encoding := asset.content, ""
+	acceptEncoding := r.Header.Get("Accept-Encoding")
+	if asset.brotli != nil && acceptsEncoding(acceptEncoding, "br") {
+		content, encoding = asset.brotli, "br"
+	} else if asset.gzip != nil && acceptsEncoding(acceptEncoding, "gzip") {
+		content, encoding = asset.gzip, "gzip"
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", asset.contentType)
+	if asset.gzip != nil || asset.brotli != nil {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+		header.Set("ETag", `"`+asset.hash+"-"+encoding+`"`)
+	} else {
+		header.Set("ETag", `"`+asset.hash+`"`)
+	}
+	if name == asset.hashedName {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		header.Set("Cache-Control", "no-cache")
+	}
+	http.ServeContent(w, r, name, s.modTime, bytes.NewReader(content))
+}
+
+func mustLoadAssets() *assetStore {
+	store, err := loadAssets(embeddedFS, "static")
+	if err != nil {
+		log.Fatalf("Error loading static assets: %v", err)
+	}
+	source, err := fs.ReadFile(embeddedFS, "static/icon.svg")
+	if err != nil {
+		log.Fatalf("Error loading icon source: %v", err)
+	}
+	icons, err := rasterizeIcons(source)
+	if err != nil {
+		log.Fatalf("Error rasterizing icons: %v", err)
+	}
+	for name, content := range icons {
+		store.add(name, content)
+	}
+	templates.AssetPaths = store.Paths()
+	return store
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"testserver/templates"
+)
+
+// Long-form case studies are Markdown files in data/case-studies named <slug>.<lang>.md,
+// rendered at /experience/{slug}. Raw HTML in them is not rendered.
+
+var caseStudyMarkdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(util.Prioritized(calloutTransformer{}, 100)),
+	),
+)
+
+// calloutTransformer styles blockquotes as metric callouts, e.g. "> **20 people** in the team"
+type calloutTransformer struct{}
+
+func (calloutTransformer) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Kind() == ast.KindBlockquote {
+			n.SetAttributeString("class", []byte("callout"))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func caseStudyFile(slug, lang string) string {
+	return fmt.Sprintf("data/case-studies/%s.%s.md", slug, lang)
+}
+
+// caseStudyLanguages lists the languages slug has a case study in
+func caseStudyLanguages(slug string) []string {
+	langs := []string{}
+	for _, lang := range templates.Languages {
+		if _, err := fs.Stat(embeddedFS, caseStudyFile(slug, lang)); err == nil {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// hasCaseStudy reports whether slug has a case study, in any language
+func hasCaseStudy(slug string) bool {
+	return len(caseStudyLanguages(slug)) > 0
+}
+
+// loadCaseStudy renders the case study for slug in lang
+func loadCaseStudy(slug, lang string) (string, bool) {
+	filename := caseStudyFile(slug, lang)
+	source, err := fs.ReadFile(embeddedFS, filename)
+	if err != nil {
+		return "", false
+	}
+	var body bytes.Buffer
+	if err := caseStudyMarkdown.Convert(source, &body); err != nil {
+		log.Printf("Error rendering %s: %v", filename, err)
+		return "", false
+	}
+	return body.String(), true
+}
+
+// serveCaseStudy renders a case study in the requested language, or in the first
+// language it is written in, so the page never claims a language it isn't in
+func serveCaseStudy(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	langs := caseStudyLanguages(slug)
+	if len(langs) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	lang := detectLanguage(r)
+	if !slices.Contains(langs, lang) {
+		lang = langs[0]
+	}
+	for _, item := range loadExperienceData(lang).ExperienceItems {
+		if item.Slug != slug {
+			continue
+		}
+		body, ok := loadCaseStudy(slug, lang)
+		if !ok {
+			break
+		}
+		templates.CaseStudyTemplate(templates.CaseStudyData{
+			Item:      item,
+			Body:      templ.Raw(body),
+			Profile:   loadProfileData(lang),
+			BaseURL:   baseURL(r),
+			Language:  lang,
+			Languages: langs,
+		}).Render(r.Context(), w)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// Case studies are listed in the sitemap with the roles they belong to, in the
+// languages they are written in
+func init() {
+	for _, item := range loadExperienceData("en").ExperienceItems {
+		if !hasCaseStudy(item.Slug) {
+			continue
+		}
+		sitePages = append(sitePages, sitePage{
+			path:      "/experience/" + item.Slug,
+			languages: caseStudyLanguages(item.Slug),
+			contentFiles: func(lang string) []string {
+				return append(contentFiles(embeddedFS, "experience", lang), caseStudyFile(item.Slug, lang))
+			},
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"testserver/templates"
+)
+
+// Server-rendered SVG charts; ?theme=dark switches to the dark palette
+
+type chartTheme struct {
+	text, muted, grid, primary, secondary string
+}
+
+var chartThemes = map[string]chartTheme{
+	"light": {text: "#374151", muted: "#6b7280", grid: "#d1d5db", primary: "#4f46e5", secondary: "#ec4899"},
+	"dark":  {text: "#e5e7eb", muted: "#9ca3af", grid: "#4b5563", primary: "#f472b6", secondary: "#818cf8"},
+}
+
+// Proficiency levels on the radar's 0-4 scale
+var levelScores = map[string]float64{
+	"intermediate": 2,
+	"advanced":     3,
+	"fluent":       3,
+	"expert":       4,
+	"native":       4,
+}
+
+func chartThemeFor(r *http.Request) chartTheme {
+	if theme, ok := chartThemes[r.URL.Query().Get("theme")]; ok {
+		return theme
+	}
+	return chartThemes["light"]
+}
+
+func writeSVG(w http.ResponseWriter, svg string) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write([]byte(svg))
+}
+
+// serveSkillsChart draws a radar of the average proficiency per skill category
+func serveSkillsChart(w http.ResponseWriter, r *http.Request) {
+	lang := detectLanguage(r)
+	writeSVG(w, skillsRadarSVG(loadSkillsData(lang), lang, chartThemeFor(r)))
+}
+
+func skillsRadarSVG(data templates.SkillsData, lang string, theme chartTheme) string {
+	const width, height, radius, rings = 680.0, 420.0, 150.0, 4
+	cx, cy := width/2, height/2
+	title := html.EscapeString(templates.GetTranslation("chart_skills_title", lang))
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %g %g" role="img" aria-label="%s" font-family="system-ui, sans-serif">`, width, height, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+
+	n := len(data.Categories)
+	if n == 0 {
+		b.WriteString(`</svg>`)
+		return b.String()
+	}
+	point := func(i int, value float64) (float64, float64) {
+		angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+		return cx + math.Cos(angle)*radius*value, cy + math.Sin(angle)*radius*value
+	}
+	polygon := func(value func(i int) float64) string {
+		points := make([]string, n)
+		for i := range n {
+			x, y := point(i, value(i))
+			points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		}
+		return strings.Join(points, " ")
+	}
+
+	for ring := 1; ring <= rings; ring++ {
+		fmt.Fprintf(&b, `<polygon points="%s" fill="none" stroke="%s" stroke-width="1"/>`,
+			polygon(func(int) float64 { return float64(ring) / rings }), theme.grid)
+	}
+	scores := make([]float64, n)
+	for i, category := range data.Categories {
+		x, y := point(i, 1)
+		fmt.Fprintf(&b, `<line x1="%g" y1="%g" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="1"/>`, cx, cy, x, y, theme.grid)
+		total := 0.0
+		for _, skill := range category.Skills {
+			total += max(levelScores[skill.Level], 1)
+		}
+		if len(category.Skills) > 0 {
+			scores[i] = total / float64(len(category.Skills)) / rings
+		}
+
+		lx, ly := point(i, 1.12)
+		anchor := "middle"
+		if lx < cx-1 {
+			anchor = "end"
+		} else if lx > cx+1 {
+			anchor = "start"
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="%s" dominant-baseline="middle" font-size="14" fill="%s">%s</text>`,
+			lx, ly, anchor, theme.text, html.EscapeString(category.Name))
+	}
+	fmt.Fprintf(&b, `<polygon points="%s" fill="%s" fill-opacity="0.25" stroke="%s" stroke-width="2"/>`,
+		polygon(func(i int) float64 { return scores[i] }), theme.primary, theme.primary)
+	for i := range n {
+		x, y := point(i, scores[i])
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="4" fill="%s"/>`, x, y, theme.primary)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+type timelineBar struct {
+	label      string
+	start, end float64 // Years, with months as fractions
+	period     string
+	education  bool
+}
+
+func newTimelineBar(label string, start templates.Month, end *templates.Month, lang string) timelineBar {
+	from := float64(start.Index()) / 12
+	return timelineBar{
+		label:  label,
+		start:  from,
+		end:    from + float64(templates.MonthsBetween(start, end, time.Now()))/12,
+		period: templates.FormatPeriod(start, end, lang),
+	}
+}
+
+// serveTimelineChart draws experience and education periods as a Gantt chart. Items
+// whose dates couldn't be parsed have no place on it and are left out.
+func serveTimelineChart(w http.ResponseWriter, r *http.Request) {
+	lang := detectLanguage(r)
+	bars := []timelineBar{}
+	for _, item := range loadExperienceData(lang).ExperienceItems {
+		if item.Start.IsZero() {
+			continue
+		}
+		bars = append(bars, newTimelineBar(item.Title+" · "+item.Company, item.Start, item.End, lang))
+	}
+	for _, item := range loadEducationData(lang).EducationItems {
+		if item.Start.IsZero() {
+			continue
+		}
+		bar := newTimelineBar(item.Title+" · "+item.Institution, item.Start, item.End, lang)
+		bar.education = true
+		bars = append(bars, bar)
+	}
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].start < bars[j].start })
+	writeSVG(w, timelineSVG(bars, lang, chartThemeFor(r)))
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+func timelineSVG(bars []timelineBar, lang string, theme chartTheme) string {
+	const width, labelWidth, rowHeight, top, right = 900.0, 330.0, 30.0, 40.0, 20.0
+	height := top + rowHeight*float64(len(bars)) + 30
+	title := html.EscapeString(templates.GetTranslation("chart_timeline_title", lang))
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %g %g" role="img" aria-label="%s" font-family="system-ui, sans-serif">`, width, height, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	if len(bars) == 0 {
+		b.WriteString(`</svg>`)
+		return b.String()
+	}
+
+	first, last := int(bars[0].start), int(math.Ceil(bars[0].end))
+	for _, bar := range bars {
+		first, last = min(first, int(bar.start)), max(last, int(math.Ceil(bar.end)))
+	}
+	last = max(last, first+1)
+	scale := (width - labelWidth - right) / float64(last-first)
+	x := func(year float64) float64 { return labelWidth + (year-float64(first))*scale }
+
+	step := 1
+	if last-first > 12 {
+		step = 2
+	}
+	for year := first; year <= last; year++ {
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%g" x2="%.1f" y2="%g" stroke="%s" stroke-width="1"/>`, x(float64(year)), top-10, x(float64(year)), height-30, theme.grid)
+		if (year-first)%step == 0 {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%g" text-anchor="middle" font-size="12" fill="%s">%d</text>`, x(float64(year)), top-16, theme.muted, year)
+		}
+	}
+	for i, bar := range bars {
+		y := top + float64(i)*rowHeight
+		color := theme.primary
+		if bar.education {
+			color = theme.secondary
+		}
+		barWidth := max((bar.end-bar.start)*scale, scale/12)
+		fmt.Fprintf(&b, `<text x="%g" y="%.1f" text-anchor="end" dominant-baseline="middle" font-size="13" fill="%s">%s</text>`,
+			labelWidth-10, y+rowHeight/2, theme.text, html.EscapeString(truncate(bar.label, 44)))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%g" rx="4" fill="%s"><title>%s: %s</title></rect>`,
+			x(bar.start), y+6, barWidth, rowHeight-12, color, html.EscapeString(bar.label), html.EscapeString(bar.period))
+	}
+
+	// Legend
+	legendY := height - 12
+	for i, entry := range []struct{ key, color string }{{"experience", theme.primary}, {"education", theme.secondary}} {
+		lx := labelWidth + float64(i)*160
+		fmt.Fprintf(&b, `<rect x="%g" y="%g" width="12" height="12" rx="2" fill="%s"/>`, lx, legendY-10, entry.color)
+		fmt.Fprintf(&b, `<text x="%g" y="%g" font-size="12" fill="%s">%s</text>`, lx+18, legendY, theme.text, html.EscapeString(templates.GetTranslation(entry.key, lang)))
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"gopkg.in/yaml.v3"
+	"testserver/templates"
+)
+
+// Content is read from data/<kind>_<lang> in JSON, YAML (.yaml or .yml) or TOML,
+// detected by extension, plus one Markdown file with front matter per item in
+// data/<kind>/<slug>.<lang>.md, named like case studies. Every format is normalized
+// to JSON and decoded into the templates structs, so their json tags and custom
+// decoders apply to all of them.
+
+var contentExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// markdownItemParser parses the body of Markdown items, whose blocks are checked
+// against what the item can hold
+var markdownItemParser = goldmark.DefaultParser()
+
+// Lists of each kind that Markdown items are added to
+var contentLists = map[string]string{
+	"experience": "ExperienceItems",
+	"education":  "EducationItems",
+	"projects":   "ProjectItems",
+}
+
+// contentError locates a content error in its file; line is 0 when unknown
+type contentError struct {
+	file string
+	line int
+	err  error
+}
+
+func (e *contentError) Error() string {
+	if e.line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.file, e.line, e.err)
+	}
+	return fmt.Sprintf("%s: %v", e.file, e.err)
+}
+
+// contentFile finds the file of kind in lang, in any supported format. It returns
+// fs.ErrNotExist when there is none, and an error when several formats exist, as
+// which one wins would otherwise depend on the extension order.
+func contentFile(fsys fs.FS, kind, lang string) (string, error) {
+	found := []string{}
+	for _, ext := range contentExtensions {
+		name := "data/" + kind + "_" + lang + ext
+		if _, err := fs.Stat(fsys, name); err == nil {
+			found = append(found, name)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fs.ErrNotExist
+	case 1:
+		return found[0], nil
+	}
+	return "", &contentError{file: found[0], err: fmt.Errorf("ambiguous %s content in %s, also in %s", kind, lang, strings.Join(found[1:], ", "))}
+}
+
+// contentFiles lists the files kind is loaded from in lang
+func contentFiles(fsys fs.FS, kind, lang string) []string {
+	files := []string{}
+	if name, err := contentFile(fsys, kind, lang); err == nil {
+		files = append(files, name)
+	}
+	items, _ := fs.Glob(fsys, markdownItems(kind, lang))
+	return append(files, items...)
+}
+
+// markdownItems is the pattern of the Markdown items of kind in lang
+func markdownItems(kind, lang string) string {
+	return "data/" + kind + "/*." + lang + ".md"
+}
+
+// loadContent decodes kind in lang from fsys into v, falling back to English, and
+// returns the file it was read from
+func loadContent(fsys fs.FS, kind, lang string, v any) (string, error) {
+	filename, err := contentFile(fsys, kind, lang)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("No %s content in %s, falling back to English", kind, lang)
+		lang = "en"
+		if filename, err = contentFile(fsys, kind, lang); errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("no %s content", kind)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	source, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return filename, err
+	}
+	doc, err := parseContent(filename, source)
+	if err != nil {
+		return filename, err
+	}
+	if err := decodeContent(doc, v); err != nil {
+		line := 0
+		if index, ok := failingItem(doc, contentLists[kind], v); ok {
+			line = itemLine(filename, source, contentLists[kind], index)
+		}
+		// Every format is decoded as JSON, which the error needn't mention
+		return filename, &contentError{file: filename, line: line, err: errors.New(strings.TrimPrefix(err.Error(), "json: "))}
+	}
+	if list, ok := contentList(v, contentLists[kind]); ok {
+		for i := range list.Len() {
+			if err := checkItem(list.Index(i).Interface()); err != nil {
+				return filename, &contentError{file: filename, line: itemLine(filename, source, contentLists[kind], i), err: err}
+			}
+		}
+	}
+	items, _ := fs.Glob(fsys, markdownItems(kind, lang))
+	for _, name := range items {
+		if err := addMarkdownItem(fsys, name, kind, v); err != nil {
+			return filename, err
+		}
+	}
+	return filename, nil
+}
+
+// parseContent parses source into a generic document according to its extension
+func parseContent(filename string, source []byte) (map[string]any, error) {
+	doc := map[string]any{}
+	switch ext := path.Ext(filename); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(source))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			var syntax *json.SyntaxError
+			if errors.As(err, &syntax) {
+				return nil, &contentError{file: filename, line: lineAt(source, int(syntax.Offset)), err: err}
+			}
+			return nil, &contentError{file: filename, err: err}
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(source, &doc); err != nil {
+			line, msg := yamlErrorLine(err)
+			return nil, &contentError{file: filename, line: line, err: errors.New(msg)}
+		}
+	case ".toml":
+		if err := toml.Unmarshal(source, &doc); err != nil {
+			var parse toml.ParseError
+			if errors.As(err, &parse) {
+				return nil, &contentError{file: filename, line: parse.Position.Line, err: errors.New(parse.Message)}
+			}
+			return nil, &contentError{file: filename, err: err}
+		}
+	default:
+		return nil, &contentError{file: filename, err: fmt.Errorf("unsupported format %q", ext)}
+	}
+	return doc, nil
+}
+
+var yamlErrorPattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+func yamlErrorLine(err error) (int, string) {
+	if match := yamlErrorPattern.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return line, match[2]
+	}
+	return 0, strings.TrimPrefix(err.Error(), "yaml: ")
+}
+
+func decodeContent(doc any, v any) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// contentList returns the slice field of the struct v points to with the JSON name key
+func contentList(v any, key string) (reflect.Value, bool) {
+	s := reflect.ValueOf(v).Elem()
+	for i := range s.NumField() {
+		name, _, _ := strings.Cut(s.Type().Field(i).Tag.Get("json"), ",")
+		if name == key && key != "" && s.Field(i).Kind() == reflect.Slice {
+			return s.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// failingItem finds the first item of the key list that doesn't decode
+func failingItem(doc map[string]any, key string, v any) (int, bool) {
+	list, ok := contentList(v, key)
+	if !ok {
+		return 0, false
+	}
+	// TOML decodes arrays of tables as []map[string]any rather than []any
+	items := reflect.ValueOf(doc[key])
+	if items.Kind() != reflect.Slice {
+		return 0, false
+	}
+	for i := range items.Len() {
+		if decodeContent(items.Index(i).Interface(), reflect.New(list.Type().Elem()).Interface()) != nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// itemLine returns the line the index-th item of the key list starts on, or 0
+func itemLine(filename string, source []byte, key string, index int) int {
+	switch path.Ext(filename) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(source))
+		if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+			return 0
+		}
+		for dec.More() {
+			name, err := dec.Token()
+			if err != nil {
+				return 0
+			}
+			if name != key {
+				var skip json.RawMessage
+				if dec.Decode(&skip) != nil {
+					return 0
+				}
+				continue
+			}
+			if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+				return 0
+			}
+			for i := 0; dec.More(); i++ {
+				if i == index {
+					offset := int(dec.InputOffset())
+					for offset < len(source) && strings.IndexByte(" \t\r\n,", source[offset]) >= 0 {
+						offset++
+					}
+					return lineAt(source, offset)
+				}
+				var skip json.RawMessage
+				if dec.Decode(&skip) != nil {
+					return 0
+				}
+			}
+		}
+	case ".yaml", ".yml":
+		var node yaml.Node
+		if yaml.Unmarshal(source, &node) != nil || len(node.Content) == 0 {
+			return 0
+		}
+		root := node.Content[0]
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if items := root.Content[i+1]; root.Content[i].Value == key && index < len(items.Content) {
+				return items.Content[index].Line
+			}
+		}
+	case ".toml":
+		header := regexp.MustCompile(`^\s*\[\[\s*` + regexp.QuoteMeta(key) + `\s*\]\]`)
+		count := 0
+		for i, line := range strings.Split(string(source), "\n") {
+			if header.MatchString(line) {
+				if count == index {
+					return i + 1
+				}
+				count++
+			}
+		}
+	}
+	return 0
+}
+
+func lineAt(source []byte, offset int) int {
+	return bytes.Count(source[:min(offset, len(source))], []byte("\n")) + 1
+}
+
+// addMarkdownItem decodes a Markdown item and adds it to the list of v, replacing
+// the item with the same slug if there is one
+func addMarkdownItem(fsys fs.FS, filename, kind string, v any) error {
+	list, ok := contentList(v, contentLists[kind])
+	if !ok {
+		return &contentError{file: filename, err: fmt.Errorf("%s content can't have Markdown items", kind)}
+	}
+	source, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return err
+	}
+	fields, body, err := parseFrontMatter(filename, string(source))
+	if err != nil {
+		return err
+	}
+	if _, ok := fields["Slug"]; !ok {
+		fields["Slug"], _, _ = strings.Cut(path.Base(filename), ".")
+	}
+	// The body is the end of the source, after the front matter
+	normalized := strings.ReplaceAll(string(source), "\r\n", "\n")
+	line := strings.Count(normalized[:len(normalized)-len(body)], "\n") + 1
+	if err := markdownBody(filename, line, kind, body, fields); err != nil {
+		return err
+	}
+	item := reflect.New(list.Type().Elem())
+	if err := decodeContent(fields, item.Interface()); err != nil {
+		return &contentError{file: filename, line: 1, err: err}
+	}
+	if err := checkItem(item.Elem().Interface()); err != nil {
+		return &contentError{file: filename, line: 1, err: err}
+	}
+	if !item.Elem().FieldByName("Slug").IsValid() {
+		return &contentError{file: filename, err: fmt.Errorf("%s items have no slug to match", kind)}
+	}
+	for i := range list.Len() {
+		if list.Index(i).FieldByName("Slug").String() == fields["Slug"] {
+			list.Index(i).Set(item.Elem())
+			return nil
+		}
+	}
+	list.Set(reflect.Append(list, item.Elem()))
+	return nil
+}
+
+// checkItem validates what decoding can't: projects link to their GitHub repository,
+// which the project cards fetch stats from
+func checkItem(item any) error {
+	if project, ok := item.(templates.ProjectItem); ok {
+		if _, ok := templates.GitHubRepo(project.GitHubLink); !ok {
+			return fmt.Errorf("project %q: GitHubLink %q is not a https://github.com/<owner>/<repo> link", project.Slug, project.GitHubLink)
+		}
+	}
+	return nil
+}
+
+// parseFrontMatter splits "---" YAML or "+++" TOML front matter from the Markdown body
+func parseFrontMatter(filename, source string) (map[string]any, string, error) {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	for delim, ext := range map[string]string{"---": ".yaml", "+++": ".toml"} {
+		if !strings.HasPrefix(source, delim+"\n") {
+			continue
+		}
+		front, body, found := strings.Cut(source[len(delim)+1:], "\n"+delim+"\n")
+		if !found {
+			front, found = strings.CutSuffix(source[len(delim)+1:], "\n"+delim)
+		}
+		if !found {
+			return nil, "", &contentError{file: filename, line: 1, err: errors.New("front matter is not closed")}
+		}
+		fields, err := parseContent(ext, []byte(front))
+		var located *contentError
+		if errors.As(err, &located) {
+			// The front matter starts on the line after the delimiter
+			return nil, "", &contentError{file: filename, line: max(located.line+1, 1), err: located.err}
+		}
+		return fields, body, err
+	}
+	return nil, "", &contentError{file: filename, line: 1, err: errors.New("missing --- or +++ front matter")}
+}
+
+// markdownBody fills fields from the Markdown body starting on line of filename: for
+// experience, the paragraphs are joined into the summary and the bullet list items are
+// the description bullets; for projects, the paragraphs are the description. Other
+// blocks, such as headings or numbered lists, are errors rather than text.
+func markdownBody(filename string, line int, kind, body string, fields map[string]any) error {
+	source := []byte(body)
+	located := func(n ast.Node, err error) error {
+		return &contentError{file: filename, line: line + lineAt(source, max(n.Pos(), 0)) - 1, err: err}
+	}
+	paragraphs, bullets := []string{}, []string{}
+	var list ast.Node
+	doc := markdownItemParser.Parse(text.NewReader(source))
+	for block := doc.FirstChild(); block != nil; block = block.NextSibling() {
+		switch block := block.(type) {
+		case *ast.Paragraph:
+			paragraphs = append(paragraphs, blockText(block, source))
+		case *ast.List:
+			if block.IsOrdered() {
+				return located(block, errors.New("numbered lists are not supported, use - bullets"))
+			}
+			if list == nil {
+				list = block
+			}
+			for item := block.FirstChild(); item != nil; item = item.NextSibling() {
+				child := item.FirstChild()
+				if item.ChildCount() != 1 || child.Kind() != ast.KindTextBlock && child.Kind() != ast.KindParagraph {
+					return located(item, errors.New("list items can only hold one paragraph of text"))
+				}
+				bullets = append(bullets, blockText(child, source))
+			}
+		default:
+			return located(block, fmt.Errorf("%s blocks are not supported", block.Kind()))
+		}
+	}
+	if len(paragraphs) == 0 && len(bullets) == 0 {
+		return nil
+	}
+	switch kind {
+	case "experience":
+		if len(paragraphs) > 0 {
+			fields["Summary"] = strings.Join(paragraphs, " ")
+		}
+		if len(bullets) > 0 {
+			fields["Description"] = bullets
+		}
+	case "projects":
+		if len(bullets) > 0 {
+			return located(list, errors.New("project descriptions can't have lists"))
+		}
+		fields["Description"] = strings.Join(paragraphs, " ")
+	default:
+		return located(doc, fmt.Errorf("%s items take no Markdown body", kind))
+	}
+	return nil
+}
+
+// blockText joins the lines of a text block with spaces, keeping the inline Markdown
+func blockText(block ast.Node, source []byte) string {
+	lines := []string{}
+	for i := range block.Lines().Len() {
+		segment := block.Lines().At(i)
+		lines = append(lines, strings.TrimSpace(string(segment.Value(source))))
+	}
+	return strings.Join(lines, " ")
+}
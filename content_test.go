@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"testserver/templates"
+)
+
+func TestLoadContentSamples(t *testing.T) {
+	samples := os.DirFS("testdata/content")
+
+	var experience templates.ExperienceData
+	filename, err := loadContent(samples, "experience", "en", &experience)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filename != "data/experience_en.yaml" {
+		t.Errorf("filename = %q", filename)
+	}
+	if len(experience.ExperienceItems) != 2 {
+		t.Fatalf("%d roles, want 2", len(experience.ExperienceItems))
+	}
+	if item := experience.ExperienceItems[0]; item.Start != (templates.Month{Year: 2022, Month: 1}) || item.End != nil || item.Description[0].Title != "Platform" {
+		t.Errorf("YAML role = %+v", item)
+	}
+	// The Markdown item replaces the YAML role with its slug
+	role := experience.ExperienceItems[1]
+	if role.Slug != "lead-engineer" || role.End == nil || *role.End != (templates.Month{Year: 2022, Month: 1}) {
+		t.Errorf("Markdown role = %+v", role)
+	}
+	if role.Summary != "Built the first programs for the engine." {
+		t.Errorf("Summary = %q", role.Summary)
+	}
+	want := []templates.Bullet{{Title: "Notes", Text: "wrote the note on Bernoulli numbers."}, {Text: "Reviewed the engine designs."}}
+	if len(role.Description) != len(want) || role.Description[0] != want[0] || role.Description[1] != want[1] {
+		t.Errorf("Description = %+v, want %+v", role.Description, want)
+	}
+
+	var education templates.EducationData
+	if _, err := loadContent(samples, "education", "en", &education); err != nil {
+		t.Fatal(err)
+	}
+	if len(education.EducationItems) != 2 || education.EducationItems[0].Qualification != "BSc" {
+		t.Errorf("TOML education = %+v", education.EducationItems)
+	}
+
+	var projects templates.ProjectsData
+	if _, err := loadContent(samples, "projects", "en", &projects); err != nil {
+		t.Fatal(err)
+	}
+	if len(projects.ProjectItems) != 2 {
+		t.Fatalf("%d projects, want 2", len(projects.ProjectItems))
+	}
+	if project := projects.ProjectItems[1]; project.Slug != "loom-cards" || project.Description != "Punched card patterns for the engine, borrowed from the Jacquard loom." {
+		t.Errorf("Markdown project = %+v", project)
+	}
+}
+
+func TestLoadContentErrorLines(t *testing.T) {
+	tests := []struct {
+		name, file, source, want string
+	}{
+		{
+			name: "JSON syntax", file: "data/experience_en.json",
+			source: "{\n\t\"ExperienceItems\": [\n\t\t{\"Title\": \"CTO\",}\n\t]\n}\n",
+			want:   "data/experience_en.json:3: ",
+		},
+		{
+			name: "JSON item", file: "data/experience_en.json",
+			source: "{\n\t\"ExperienceItems\": [\n\t\t{\"Title\": \"CTO\"},\n\t\t{\"Title\": \"Lead\", \"Skills\": \"golang\"}\n\t]\n}\n",
+			want:   "data/experience_en.json:4: ",
+		},
+		{
+			name: "YAML syntax", file: "data/experience_en.yaml",
+			source: "ExperienceItems:\n  - Title: CTO\n    Company: Engines: Analytical\n",
+			want:   "data/experience_en.yaml:3: ",
+		},
+		{
+			name: "YAML item", file: "data/experience_en.yml",
+			source: "ExperienceItems:\n  - Title: CTO\n  - Title: Lead\n    Skills: golang\n",
+			want:   "data/experience_en.yml:3: ",
+		},
+		{
+			name: "TOML syntax", file: "data/experience_en.toml",
+			source: "[[ExperienceItems]]\nTitle = \"CTO\"\nCompany = \n",
+			want:   "data/experience_en.toml:3: ",
+		},
+		{
+			name: "TOML item", file: "data/experience_en.toml",
+			source: "[[ExperienceItems]]\nTitle = \"CTO\"\n\n[[ExperienceItems]]\nTitle = \"Lead\"\nSkills = \"golang\"\n",
+			want:   "data/experience_en.toml:4: ",
+		},
+		{
+			name: "YAML front matter", file: "data/experience/cto.en.md",
+			source: "---\nTitle: CTO\nCompany: Engines: Analytical\n---\nSummary\n",
+			want:   "data/experience/cto.en.md:3: ",
+		},
+		{
+			name: "TOML front matter", file: "data/experience/cto.en.md",
+			source: "+++\nTitle = \"CTO\"\nCompany =\n+++\nSummary\n",
+			want:   "data/experience/cto.en.md:3: ",
+		},
+		{
+			name: "unclosed front matter", file: "data/experience/cto.en.md",
+			source: "---\nTitle: CTO\n",
+			want:   "data/experience/cto.en.md:1: front matter is not closed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{tt.file: {Data: []byte(tt.source)}}
+			if strings.HasPrefix(tt.file, "data/experience/") {
+				// Markdown items are added to the list of a content file
+				fsys["data/experience_en.json"] = &fstest.MapFile{Data: []byte(`{"ExperienceItems": []}`)}
+			}
+			var data templates.ExperienceData
+			_, err := loadContent(fsys, "experience", "en", &data)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentFileAmbiguous(t *testing.T) {
+	fsys := fstest.MapFS{
+		"data/skills_en.json": {Data: []byte("{}")},
+		"data/skills_en.yaml": {Data: []byte("{}")},
+		"data/profile_en.yml": {Data: []byte("{}")},
+	}
+	_, err := contentFile(fsys, "skills", "en")
+	if err == nil || err.Error() != "data/skills_en.json: ambiguous skills content in en, also in data/skills_en.yaml" {
+		t.Errorf("error = %v", err)
+	}
+	var skills templates.SkillsData
+	if _, err := loadContent(fsys, "skills", "en", &skills); err == nil {
+		t.Error("loadContent accepted ambiguous content")
+	}
+	if name, err := contentFile(fsys, "profile", "en"); err != nil || name != "data/profile_en.yml" {
+		t.Errorf("contentFile = %q, %v", name, err)
+	}
+	if _, err := contentFile(fsys, "projects", "en"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestLoadContentRequiresGitHubLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "Markdown without link",
+			fsys: fstest.MapFS{
+				"data/projects_en.json":          {Data: []byte(`{"ProjectItems": []}`)},
+				"data/projects/loom-cards.en.md": {Data: []byte("+++\nTitle = \"Loom Cards\"\n+++\nCards.\n")},
+			},
+			want: `data/projects/loom-cards.en.md:1: project "loom-cards": GitHubLink "" is not`,
+		},
+		{
+			name: "JSON with another host",
+			fsys: fstest.MapFS{
+				"data/projects_en.json": {Data: []byte("{\n\t\"ProjectItems\": [\n\t\t{\"Slug\": \"a\", \"GitHubLink\": \"https://github.com/ada/a\"},\n\t\t{\"Slug\": \"b\", \"GitHubLink\": \"https://gitlab.com/ada/b\"}\n\t]\n}\n")},
+			},
+			want: `data/projects_en.json:4: project "b": GitHubLink "https://gitlab.com/ada/b" is not`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data templates.ProjectsData
+			_, err := loadContent(tt.fsys, "projects", "en", &data)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownItemReplacesSameSlug(t *testing.T) {
+	fsys := fstest.MapFS{
+		"data/experience_en.json":        {Data: []byte(`{"ExperienceItems": [{"Slug": "cto", "Title": "CTO", "Start": "2020"}, {"Slug": "dev", "Title": "Developer", "Start": "2018"}]}`)},
+		"data/experience/cto.en.md":      {Data: []byte("---\nTitle: Chief Technology Officer\nStart: \"2021\"\n---\n")},
+		"data/education_en.json":         {Data: []byte(`{"EducationItems": [{"Slug": "msc", "Title": "MSc", "Start": "2015"}, {"Title": "BSc", "Start": "2012"}]}`)},
+		"data/education/msc.en.md":       {Data: []byte("---\nTitle: MSc Mathematics\nStart: \"2015\"\n---\n")},
+		"data/projects_en.json":          {Data: []byte(`{"ProjectItems": [{"Slug": "note-g", "Title": "Note G", "GitHubLink": "https://github.com/ada/note-g"}]}`)},
+		"data/projects/note-g.en.md":     {Data: []byte("+++\nTitle = \"Note G, annotated\"\nGitHubLink = \"https://github.com/ada/note-g\"\n+++\n")},
+		"data/projects/loom-cards.en.md": {Data: []byte("+++\nTitle = \"Loom Cards\"\nGitHubLink = \"https://github.com/ada/loom-cards\"\n+++\n")},
+	}
+
+	var experience templates.ExperienceData
+	if _, err := loadContent(fsys, "experience", "en", &experience); err != nil {
+		t.Fatal(err)
+	}
+	if items := experience.ExperienceItems; len(items) != 2 || items[0].Title != "Chief Technology Officer" || items[1].Title != "Developer" {
+		t.Errorf("experience = %+v", items)
+	}
+
+	var education templates.EducationData
+	if _, err := loadContent(fsys, "education", "en", &education); err != nil {
+		t.Fatal(err)
+	}
+	if items := education.EducationItems; len(items) != 2 || items[0].Title != "MSc Mathematics" || items[1].Title != "BSc" {
+		t.Errorf("education = %+v", items)
+	}
+
+	var projects templates.ProjectsData
+	if _, err := loadContent(fsys, "projects", "en", &projects); err != nil {
+		t.Fatal(err)
+	}
+	if items := projects.ProjectItems; len(items) != 2 || items[0].Title != "Note G, annotated" || items[1].Slug != "loom-cards" {
+		t.Errorf("projects = %+v", items)
+	}
+}
+
+func TestMarkdownBodyRefusesUnsupportedBlocks(t *testing.T) {
+	tests := []struct {
+		name, file, body, want string
+	}{
+		{name: "heading", file: "data/experience/cto.en.md", body: "Summary.\n\n# Results\n", want: "data/experience/cto.en.md:7: Heading blocks are not supported"},
+		{name: "numbered list", file: "data/experience/cto.en.md", body: "Summary.\n\n1. First\n2. Second\n", want: "data/experience/cto.en.md:7: numbered lists are not supported"},
+		{name: "code block", file: "data/experience/cto.en.md", body: "```\ncode\n```\n", want: "data/experience/cto.en.md:5: FencedCodeBlock blocks are not supported"},
+		{name: "quote", file: "data/experience/cto.en.md", body: "- One\n\n> Quote\n", want: "data/experience/cto.en.md:7: Blockquote blocks are not supported"},
+		{name: "nested list", file: "data/experience/cto.en.md", body: "- One\n- Two\n  - Nested\n", want: "data/experience/cto.en.md:6: list items can only hold one paragraph"},
+		{name: "project list", file: "data/projects/note-g.en.md", body: "Text.\n\n- One\n", want: "data/projects/note-g.en.md:7: project descriptions can't have lists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"data/experience_en.json": {Data: []byte(`{"ExperienceItems": []}`)},
+				"data/projects_en.json":   {Data: []byte(`{"ProjectItems": []}`)},
+				tt.file:                   {Data: []byte("---\nTitle: Item\nGitHubLink: https://github.com/ada/item\n---\n" + tt.body)},
+			}
+			kind, _, _ := strings.Cut(strings.TrimPrefix(tt.file, "data/"), "/")
+			list := map[string]any{"experience": &templates.ExperienceData{}, "projects": &templates.ProjectsData{}}[kind]
+			_, err := loadContent(fsys, kind, "en", list)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"testserver/templates"
+)
+
+// Migration of the legacy free-text Period fields ("September 2013 - 2016, RNCP Level 1")
+// to structured dates, applied when content files still use them
+
+var legacyPeriod = regexp.MustCompile(`^\s*(?:(\p{L}+\.?)\s+)?(\d{4})\s*[-–]\s*(?:(?:(\p{L}+\.?)\s+)?(\d{4})|(\p{L}[\p{L}' ]*))?\s*(?:,\s*(.*?))?\s*$`)
+
+// Month names in English and French, full and abbreviated, in normalized form
+var monthNames = map[string]time.Month{}
+
+func init() {
+	names := [][]string{
+		{"january", "jan", "janvier", "janv"},
+		{"february", "feb", "fevrier", "fevr"},
+		{"march", "mar", "mars"},
+		{"april", "apr", "avril", "avr"},
+		{"may", "mai"},
+		{"june", "jun", "juin"},
+		{"july", "jul", "juillet", "juil"},
+		{"august", "aug", "aout"},
+		{"september", "sep", "sept", "septembre"},
+		{"october", "oct", "octobre"},
+		{"november", "nov", "novembre"},
+		{"december", "dec", "decembre"},
+	}
+	for i, forms := range names {
+		for _, name := range forms {
+			monthNames[name] = time.Month(i + 1)
+		}
+	}
+}
+
+// Words marking an ongoing period
+var presentWords = map[string]bool{"present": true, "now": true, "today": true, "aujourd'hui": true, "aujourdhui": true, "actuel": true}
+
+func parseLegacyMonth(name, year string) (templates.Month, error) {
+	y, _ := strconv.Atoi(year)
+	if name == "" {
+		return templates.Month{Year: y}, nil
+	}
+	month, ok := monthNames[strings.TrimSuffix(normalizeSkill(name), ".")]
+	if !ok {
+		return templates.Month{}, errors.New("unknown month " + name)
+	}
+	return templates.Month{Year: y, Month: month}, nil
+}
+
+// parsePeriod splits a legacy period into its start, end (nil when ongoing) and the
+// trailing text after the first comma
+func parsePeriod(period string) (templates.Month, *templates.Month, string, error) {
+	match := legacyPeriod.FindStringSubmatch(period)
+	if match == nil {
+		return templates.Month{}, nil, "", errors.New("unrecognized period " + strconv.Quote(period))
+	}
+	start, err := parseLegacyMonth(match[1], match[2])
+	if err != nil {
+		return templates.Month{}, nil, "", err
+	}
+	var end *templates.Month
+	switch {
+	case match[4] != "":
+		month, err := parseLegacyMonth(match[3], match[4])
+		if err != nil {
+			return templates.Month{}, nil, "", err
+		}
+		end = &month
+	case match[5] != "" && !presentWords[normalizeSkill(match[5])]:
+		return templates.Month{}, nil, "", errors.New("unrecognized end of period " + strconv.Quote(period))
+	}
+	return start, end, match[6], nil
+}
+
+// migrateExperience fills Start, End and Location from legacy Period strings and
+// sorts roles from the most recent
+func migrateExperience(data *templates.ExperienceData, filename string) {
+	for i := range data.ExperienceItems {
+		item := &data.ExperienceItems[i]
+		if !item.Start.IsZero() || item.Period == "" {
+			continue
+		}
+		start, end, rest, err := parsePeriod(item.Period)
+		if err != nil {
+			log.Printf("%s: %s: %v", filename, item.Title, err)
+			continue
+		}
+		item.Start, item.End, item.Period = start, end, ""
+		if item.Location == "" {
+			item.Location = rest
+		}
+	}
+	sort.SliceStable(data.ExperienceItems, func(i, j int) bool {
+		return laterPeriod(data.ExperienceItems[i].Start, data.ExperienceItems[i].End, data.ExperienceItems[j].Start, data.ExperienceItems[j].End)
+	})
+}
+
+// migrateEducation fills Start, End and Qualification from legacy Period strings and
+// sorts entries from the most recent
+func migrateEducation(data *templates.EducationData, filename string) {
+	for i := range data.EducationItems {
+		item := &data.EducationItems[i]
+		if !item.Start.IsZero() || item.Period == "" {
+			continue
+		}
+		start, end, rest, err := parsePeriod(item.Period)
+		if err != nil {
+			log.Printf("%s: %s: %v", filename, item.Title, err)
+			continue
+		}
+		item.Start, item.End, item.Period = start, end, ""
+		if item.Qualification == "" {
+			item.Qualification = rest
+		}
+	}
+	sort.SliceStable(data.EducationItems, func(i, j int) bool {
+		return laterPeriod(data.EducationItems[i].Start, data.EducationItems[i].End, data.EducationItems[j].Start, data.EducationItems[j].End)
+	})
+}
+
+// laterPeriod orders ongoing periods first, then by start and end, most recent first.
+// Periods without a start, whose dates couldn't be parsed, come last.
+func laterPeriod(startA templates.Month, endA *templates.Month, startB templates.Month, endB *templates.Month) bool {
+	if startA.IsZero() || startB.IsZero() {
+		return !startA.IsZero() && startB.IsZero()
+	}
+	if (endA == nil) != (endB == nil) {
+		return endA == nil
+	}
+	if startA.Index() != startB.Index() {
+		return startA.Index() > startB.Index()
+	}
+	return endA != nil && endA.Index() > endB.Index()
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"testserver/templates"
+)
+
+func TestMigrateExperienceOrdersUnparsedDatesLast(t *testing.T) {
+	end := templates.Month{Year: 2020, Month: 1}
+	data := templates.ExperienceData{ExperienceItems: []templates.ExperienceItem{
+		{Slug: "undated", Period: "A while"},
+		{Slug: "past", Start: templates.Month{Year: 2018, Month: 1}, End: &end},
+		{Slug: "current", Start: templates.Month{Year: 2021, Month: 1}},
+	}}
+	migrateExperience(&data, "data/experience_en.json")
+
+	slugs := []string{}
+	for _, item := range data.ExperienceItems {
+		slugs = append(slugs, item.Slug)
+	}
+	if got := strings.Join(slugs, " "); got != "current past undated" {
+		t.Errorf("order = %s, want current past undated", got)
+	}
+}
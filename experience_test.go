@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"testserver/templates"
+)
+
+var toggleURL = regexp.MustCompile(`hx-get="(/cv/experience/(?:detail|collapse)/0[^"]*)"`)
+
+// nextToggle returns the URL the role 0 toggle in page swaps it with
+func nextToggle(t *testing.T, page string) string {
+	t.Helper()
+	match := toggleURL.FindStringSubmatch(page)
+	if match == nil {
+		t.Fatalf("no toggle for role 0 in %s", page)
+	}
+	return html.UnescapeString(match[1])
+}
+
+func TestRoleToggleKeepsSkillFilter(t *testing.T) {
+	router := chi.NewRouter()
+	router.Get("/cv/experience/detail/{id}", serveExperienceRole(true))
+	router.Get("/cv/experience/collapse/{id}", serveExperienceRole(false))
+	get := func(target string) string {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d", target, rec.Code)
+		}
+		return rec.Body.String()
+	}
+
+	data := loadExperienceData("en")
+	skill, ok := findSkill(loadSkillsData("en"), "stripe")
+	if !ok {
+		t.Fatal("no stripe skill")
+	}
+	filterExperience(&data, skill)
+	data.Language = "en"
+	var page bytes.Buffer
+	if err := templates.ExperienceTemplate(data).Render(context.Background(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page.String(), "skill-match") {
+		t.Fatal("the filtered timeline highlights no bullets")
+	}
+
+	collapse := nextToggle(t, page.String())
+	if collapse != "/cv/experience/collapse/0?lang=en&skill=stripe" {
+		t.Errorf("collapse URL = %q", collapse)
+	}
+	summary := get(collapse)
+	if !strings.Contains(summary, `name="skill" value="stripe"`) {
+		t.Error("the collapsed role's fallback form drops the skill")
+	}
+	expand := nextToggle(t, summary)
+	if expand != "/cv/experience/detail/0?lang=en&skill=stripe" {
+		t.Errorf("expand URL = %q", expand)
+	}
+	if detail := get(expand); !strings.Contains(detail, "skill-match") {
+		t.Error("the re-expanded role lost its highlighted bullets")
+	}
+	if detail := get("/cv/experience/detail/0?lang=en"); strings.Contains(detail, "skill-match") {
+		t.Error("an unfiltered role has highlighted bullets")
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"testserver/templates"
+)
+
+// Gemini capsule (gemini://) serving the CV as gemtext, one set of pages per language
+
+const (
+	geminiMaxRequest = 1024
+	geminiTimeout    = 10 * time.Second
+)
+
+// Gemini status codes
+const (
+	geminiSuccess      = 20
+	geminiRedirect     = 31
+	geminiNotFound     = 51
+	geminiProxyRefused = 53
+	geminiBadRequest   = 59
+)
+
+// Pages under /{lang}/, in the order they are linked from the index
+var geminiPages = []struct {
+	path, title string
+}{
+	{"experience", "professional_experience"},
+	{"education", "education"},
+	{"projects", "personal_projects"},
+}
+
+// loadOrCreateCertificate reads a TLS certificate, generating a self-signed one for
+// host on first run; Gemini clients pin certificates on first use
+func loadOrCreateCertificate(certPath, keyPath, host string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return cert, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return tls.Certificate{}, err
+	}
+	log.Printf("Generated Gemini certificate %s for %s", certPath, host)
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+type geminiServer struct {
+	host string // Accepted request host; any host when empty
+	tls  *tls.Config
+}
+
+func newGeminiServer(certPath, keyPath, host string) (*geminiServer, error) {
+	certHost := host
+	if certHost == "" {
+		certHost = "localhost"
+	}
+	cert, err := loadOrCreateCertificate(certPath, keyPath, certHost)
+	if err != nil {
+		return nil, err
+	}
+	return &geminiServer{
+		host: host,
+		tls:  &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12},
+	}, nil
+}
+
+func (g *geminiServer) ListenAndServe(addr string) error {
+	listener, err := tls.Listen("tcp", addr, g.tls)
+	if err != nil {
+		return err
+	}
+	log.Printf("Gemini capsule listening on %s", addr)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go g.serveConn(conn)
+	}
+}
+
+func (g *geminiServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(geminiTimeout))
+	request, err := readGeminiRequest(conn)
+	var status int
+	var meta, body string
+	if err != nil {
+		status, meta = geminiBadRequest, err.Error()
+	} else {
+		status, meta, body = g.respond(request)
+	}
+	fmt.Fprintf(conn, "%d %s\r\n%s", status, meta, body)
+	log.Printf("gemini %q %d", request, status)
+}
+
+// readGeminiRequest reads the single request line: an absolute URL of at most
+// 1024 bytes terminated by CRLF
+func readGeminiRequest(r io.Reader) (string, error) {
+	line, err := bufio.NewReaderSize(io.LimitReader(r, geminiMaxRequest+2), geminiMaxRequest+2).ReadString('\n')
+	if err != nil {
+		return "", errors.New("request line too long or not terminated by CRLF")
+	}
+	request, ok := strings.CutSuffix(line, "\r\n")
+	if !ok {
+		return "", errors.New("request line must end with CRLF")
+	}
+	return request, nil
+}
+
+// respond maps a request URL to a status, meta line and gemtext body
+func (g *geminiServer) respond(request string) (int, string, string) {
+	u, err := url.Parse(request)
+	if err != nil || !u.IsAbs() || u.Host == "" || u.User != nil {
+		return geminiBadRequest, "Request must be an absolute URL without userinfo", ""
+	}
+	if u.Scheme != "gemini" || g.host != "" && !strings.EqualFold(u.Hostname(), g.host) {
+		return geminiProxyRefused, "Proxy requests are not supported", ""
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if parts[0] == "" {
+		return geminiRedirect, "/en/", ""
+	}
+	lang := parts[0]
+	if !slices.Contains(templates.Languages, lang) || len(parts) > 2 {
+		return geminiNotFound, "Not found", ""
+	}
+	if len(parts) == 1 {
+		if !strings.HasSuffix(u.Path, "/") {
+			return geminiRedirect, "/" + lang + "/", ""
+		}
+		return geminiSuccess, "text/gemini; lang=" + lang, geminiIndex(lang)
+	}
+	var body string
+	switch parts[1] {
+	case "experience":
+		body = geminiExperience(lang)
+	case "education":
+		body = geminiEducation(lang)
+	case "projects":
+		body = geminiProjects(lang)
+	default:
+		return geminiNotFound, "Not found", ""
+	}
+	return geminiSuccess, "text/gemini; lang=" + lang, body
+}
+
+func geminiIndex(lang string) string {
+	profile := loadProfileData(lang)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n", profile.Name, profile.JobTitle)
+	fmt.Fprintf(&b, "## %s\n\n%s\n\n", profile.Title, templates.PlainText(profile.Text))
+	for _, page := range geminiPages {
+		fmt.Fprintf(&b, "=> /%s/%s %s\n", lang, page.path, templates.GetTranslation(page.title, lang))
+	}
+	b.WriteString("\n")
+	for _, link := range profile.Links {
+		fmt.Fprintf(&b, "=> %s %s\n", link.URL, link.Name)
+	}
+	b.WriteString("\n## " + templates.GetTranslation("skills", lang) + "\n")
+	for _, category := range loadSkillsData(lang).Categories {
+		fmt.Fprintf(&b, "\n### %s\n\n", category.Name)
+		for _, skill := range category.Skills {
+			fmt.Fprintf(&b, "* %s (%s)\n", skill.Name, templates.GetTranslation("level_"+skill.Level, lang))
+		}
+	}
+	b.WriteString("\n")
+	for _, other := range templates.Languages {
+		if other != lang {
+			fmt.Fprintf(&b, "=> /%s/ %s\n", other, strings.ToUpper(other))
+		}
+	}
+	return b.String()
+}
+
+func geminiExperience(lang string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", templates.GetTranslation("professional_experience", lang))
+	for _, item := range loadExperienceData(lang).ExperienceItems {
+		fmt.Fprintf(&b, "\n## %s — %s\n\n%s\n\n%s\n\n", item.Title, item.Company,
+			strings.Join(nonEmpty(item.PeriodLabel(lang), item.Duration(lang), item.Location), " · "), templates.PlainText(item.Summary))
+		for _, desc := range item.Description {
+			fmt.Fprintf(&b, "* %s\n", desc)
+		}
+	}
+	fmt.Fprintf(&b, "\n=> /%s/ %s\n", lang, loadProfileData(lang).Name)
+	return b.String()
+}
+
+func geminiEducation(lang string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", templates.GetTranslation("education", lang))
+	for _, item := range loadEducationData(lang).EducationItems {
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n%s\n", item.Title, item.Institution, strings.Join(nonEmpty(item.PeriodLabel(lang), item.Qualification), " · "))
+	}
+	fmt.Fprintf(&b, "\n=> /%s/ %s\n", lang, loadProfileData(lang).Name)
+	return b.String()
+}
+
+func geminiProjects(lang string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", templates.GetTranslation("personal_projects", lang))
+	for _, item := range loadProjectsData(lang).ProjectItems {
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", item.Title, templates.PlainText(item.Description))
+		if _, ok := templates.GitHubRepo(item.GitHubLink); ok {
+			fmt.Fprintf(&b, "\n=> %s %s\n", item.GitHubLink, templates.GetTranslation("view_on_github", lang))
+		}
+	}
+	fmt.Fprintf(&b, "\n=> /%s/ %s\n", lang, loadProfileData(lang).Name)
+	return b.String()
+}
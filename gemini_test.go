@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReadGeminiRequest(t *testing.T) {
+	longest := "gemini://cv.example/" + strings.Repeat("a", geminiMaxRequest-len("gemini://cv.example/"))
+	for _, tc := range []struct {
+		input, want string
+		ok          bool
+	}{
+		{"gemini://cv.example/en/\r\n", "gemini://cv.example/en/", true},
+		{longest + "\r\n", longest, true},
+		{longest + "a\r\n", "", false},
+		{"gemini://cv.example/en/\n", "", false},
+		{"gemini://cv.example/en/", "", false},
+		{"", "", false},
+	} {
+		got, err := readGeminiRequest(strings.NewReader(tc.input))
+		if got != tc.want || (err == nil) != tc.ok {
+			t.Errorf("readGeminiRequest(%.40q) = %q, %v; want %q, ok %v", tc.input, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestGeminiRespond(t *testing.T) {
+	g := &geminiServer{host: "cv.example"}
+	for _, tc := range []struct {
+		request string
+		status  int
+		meta    string
+	}{
+		{"gemini://cv.example/en/", geminiSuccess, "text/gemini; lang=en"},
+		{"gemini://cv.example/fr/experience", geminiSuccess, "text/gemini; lang=fr"},
+		{"gemini://CV.example:1965/en/projects", geminiSuccess, "text/gemini; lang=en"},
+		{"gemini://cv.example/", geminiRedirect, "/en/"},
+		{"gemini://cv.example/en", geminiRedirect, "/en/"},
+		{"gemini://cv.example/de/", geminiNotFound, "Not found"},
+		{"gemini://cv.example/en/unknown", geminiNotFound, "Not found"},
+		{"gemini://cv.example/en/experience/extra", geminiNotFound, "Not found"},
+		{"/en/", geminiBadRequest, "Request must be an absolute URL without userinfo"},
+		{"gemini://user@cv.example/en/", geminiBadRequest, "Request must be an absolute URL without userinfo"},
+		{"gemini://%zz", geminiBadRequest, "Request must be an absolute URL without userinfo"},
+		{"https://cv.example/en/", geminiProxyRefused, "Proxy requests are not supported"},
+		{"gemini://other.example/en/", geminiProxyRefused, "Proxy requests are not supported"},
+	} {
+		status, meta, body := g.respond(tc.request)
+		if status != tc.status || meta != tc.meta {
+			t.Errorf("respond(%q) = %d %q, want %d %q", tc.request, status, meta, tc.status, tc.meta)
+		}
+		if status != geminiSuccess && body != "" {
+			t.Errorf("respond(%q) has a body with status %d", tc.request, status)
+		}
+	}
+}
+
+// gemtextLine matches the line types of text/gemini outside preformatted blocks
+var gemtextLine = regexp.MustCompile(`^(|=> \S+( .+)?|#{1,3} .+|\* .+|> .*|[^=#*>` + "`" + `].*)$`)
+
+func TestGemtext(t *testing.T) {
+	for _, lang := range []string{"en", "fr"} {
+		pages := map[string]string{
+			"index":      geminiIndex(lang),
+			"experience": geminiExperience(lang),
+			"education":  geminiEducation(lang),
+			"projects":   geminiProjects(lang),
+		}
+		for name, body := range pages {
+			if !strings.HasPrefix(body, "# ") {
+				t.Errorf("%s/%s doesn't start with a heading", lang, name)
+			}
+			for i, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+				if !gemtextLine.MatchString(line) {
+					t.Errorf("%s/%s line %d is not gemtext: %q", lang, name, i+1, line)
+				}
+				if strings.Contains(line, "**") || strings.Contains(line, "](") {
+					t.Errorf("%s/%s line %d has inline Markdown: %q", lang, name, i+1, line)
+				}
+			}
+		}
+		for _, page := range geminiPages {
+			if link := "=> /" + lang + "/" + page.path + " "; !strings.Contains(pages["index"], link) {
+				t.Errorf("%s index has no %q link", lang, link)
+			}
+		}
+		for _, item := range loadExperienceData(lang).ExperienceItems {
+			if !strings.Contains(pages["experience"], "## "+item.Title+" — "+item.Company+"\n") {
+				t.Errorf("%s experience has no heading for %s", lang, item.Slug)
+			}
+		}
+	}
+}
+
+func TestGeminiServeConn(t *testing.T) {
+	dir := t.TempDir()
+	g, err := newGeminiServer(filepath.Join(dir, "gemini.crt"), filepath.Join(dir, "gemini.key"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", g.tls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go g.serveConn(conn)
+		}
+	}()
+
+	fetch := func(request string) (string, string) {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{InsecureSkipVerify: true}) // Gemini certificates are self-signed
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		io.WriteString(conn, request)
+		reader := bufio.NewReader(conn)
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("%q: %v", request, err)
+		}
+		body, _ := io.ReadAll(reader)
+		return header, string(body)
+	}
+
+	host := "gemini://" + listener.Addr().(*net.TCPAddr).String()
+	if header, body := fetch(host + "/en/experience\r\n"); header != "20 text/gemini; lang=en\r\n" || !strings.HasPrefix(body, "# ") {
+		t.Errorf("experience: %q, body %.20q", header, body)
+	}
+	if header, body := fetch(host + "/xx/\r\n"); header != "51 Not found\r\n" || body != "" {
+		t.Errorf("unknown language: %q, body %q", header, body)
+	}
+	if header, _ := fetch(host + "/en/\n"); !strings.HasPrefix(header, "59 ") {
+		t.Errorf("request without CRLF: %q", header)
+	}
+	if header, _ := fetch(host + "/" + strings.Repeat("a", geminiMaxRequest) + "\r\n"); !strings.HasPrefix(header, "59 ") {
+		t.Errorf("request over 1024 bytes: %q", header)
+	}
+}
@@ -3,6 +3,19 @@ module testserver
 go 1.24.1
 
 require (
-	github.com/a-h/templ v0.3.943 // indirect
-	github.com/go-chi/chi/v5 v5.2.2 // indirect
+	github.com/BurntSushi/toml v1.6.0
+	github.com/a-h/templ v0.3.943
+	github.com/andybalholm/brotli v1.1.0
+	github.com/go-chi/chi/v5 v5.2.2
+	github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c
+	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef
+	github.com/yuin/goldmark v1.8.2
+	golang.org/x/crypto v0.40.0
+	golang.org/x/image v0.25.0
+	golang.org/x/net v0.42.0
+	golang.org/x/text v0.27.0
+	gopkg.in/yaml.v3 v3.0.1
+	willnorris.com/go/microformats v1.2.0
 )
+
+require golang.org/x/sys v0.34.0 // indirect
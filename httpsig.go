@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HTTP Signatures (draft-cavage-http-signatures-12) as used between ActivityPub servers
+
+// Allowed difference between a signed Date header and our clock
+const signatureMaxSkew = 12 * time.Hour
+
+// bodyDigest returns the Digest header value for body
+func bodyDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// signingString builds the string covered by the signature from the listed headers
+func signingString(r *http.Request, headers []string) (string, error) {
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(r.Method), r.URL.RequestURI()))
+		case "host":
+			host := r.Host
+			if host == "" {
+				host = r.URL.Host
+			}
+			lines = append(lines, "host: "+host)
+		default:
+			value := r.Header.Get(h)
+			if value == "" {
+				return "", fmt.Errorf("signed header %q missing", h)
+			}
+			lines = append(lines, h+": "+value)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// signRequest adds Date, Digest (when there is a body) and Signature headers to r
+func signRequest(r *http.Request, body []byte, keyID string, key *rsa.PrivateKey) error {
+	r.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	headers := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		r.Header.Set("Digest", bodyDigest(body))
+		headers = append(headers, "digest")
+	}
+	toSign, err := signingString(r, headers)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(toSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// parseSignature splits a Signature header into its parameters
+func parseSignature(header string) map[string]string {
+	params := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[name] = strings.Trim(value, `"`)
+		}
+	}
+	return params
+}
+
+// verifyRequest checks the signature on r against the public key returned by lookup
+// for the signature's keyId, and that the body matches the signed digest.
+// It returns the keyId that signed the request.
+func verifyRequest(r *http.Request, body []byte, lookup func(keyID string) (*rsa.PublicKey, error)) (string, error) {
+	params := parseSignature(r.Header.Get("Signature"))
+	keyID, encoded := params["keyId"], params["signature"]
+	if keyID == "" || encoded == "" {
+		return "", errors.New("missing or malformed Signature header")
+	}
+	headers := strings.Fields(params["headers"])
+	if len(headers) == 0 {
+		headers = []string{"date"}
+	}
+	// Covering host keeps a signature from being replayed against another server
+	for _, required := range []string{"(request-target)", "host", "date"} {
+		if !slices.Contains(headers, required) {
+			return "", errors.New("signature must cover (request-target), host and date")
+		}
+	}
+	date, err := http.ParseTime(r.Header.Get("Date"))
+	if err != nil {
+		return "", fmt.Errorf("invalid Date header: %w", err)
+	}
+	if skew := time.Since(date); skew > signatureMaxSkew || skew < -signatureMaxSkew {
+		return "", errors.New("Date header outside the allowed window")
+	}
+	if len(body) > 0 {
+		if !slices.Contains(headers, "digest") {
+			return "", errors.New("signature must cover the digest of the body")
+		}
+		if r.Header.Get("Digest") != bodyDigest(body) {
+			return "", errors.New("Digest header does not match the body")
+		}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	toVerify, err := signingString(r, headers)
+	if err != nil {
+		return "", err
+	}
+	key, err := lookup(keyID)
+	if err != nil {
+		return "", fmt.Errorf("fetching key %s: %w", keyID, err)
+	}
+	sum := sha256.Sum256([]byte(toVerify))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], signature); err != nil {
+		return "", errors.New("signature verification failed")
+	}
+	return keyID, nil
+}
+
+func encodePublicKey(key *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	pem.Encode(&buf, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return buf.String(), nil
+}
+
+func decodePublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block in public key")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		// Some servers publish PKCS#1 keys
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not RSA")
+	}
+	return key, nil
+}
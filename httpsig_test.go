@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signCovering signs r like signRequest, but covering the given headers
+func signCovering(t *testing.T, r *http.Request, headers []string, key *rsa.PrivateKey) {
+	t.Helper()
+	toSign, err := signingString(r, headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(toSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Signature", fmt.Sprintf(`keyId="test#key",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+}
+
+func TestVerifyRequestCoveredHeaders(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(string) (*rsa.PublicKey, error) { return &key.PublicKey, nil }
+	body := []byte(`{"type":"Follow"}`)
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "https://cv.example/ap/inbox", bytes.NewReader(body))
+		date := time.Now().UTC().Format(http.TimeFormat)
+		r.Header.Set("Date", date)
+		r.Header.Set("X-Date", date)
+		r.Header.Set("Digest", bodyDigest(body))
+		r.Header.Set("Digest-Foo", "bar")
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		headers []string
+		host    string // Host the request is replayed against, if any
+		want    string
+	}{
+		{name: "all covered", headers: []string{"(request-target)", "host", "date", "digest"}},
+		{name: "x-date for date", headers: []string{"(request-target)", "host", "x-date", "digest"}, want: "must cover"},
+		{name: "digest-foo for digest", headers: []string{"(request-target)", "host", "date", "digest-foo"}, want: "digest of the body"},
+		{name: "no host", headers: []string{"(request-target)", "date", "digest"}, want: "must cover"},
+		{name: "replayed on another host", headers: []string{"(request-target)", "host", "date", "digest"}, host: "other.example", want: "verification failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest()
+			signCovering(t, r, tt.headers, key)
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+			_, err := verifyRequest(r, body, lookup)
+			if tt.want == "" && err != nil {
+				t.Errorf("verifyRequest: %v", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("verifyRequest: %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/draw"
+)
+
+// An app icon rasterized from static/icon.svg
+type appIcon struct {
+	name     string
+	size     int
+	maskable bool
+}
+
+var appIcons = []appIcon{
+	{name: "icon-192.png", size: 192},
+	{name: "icon-512.png", size: 512},
+	{name: "icon-maskable-512.png", size: 512, maskable: true},
+}
+
+// rasterizeIcons renders every app icon from the source SVG.
+// Maskable icons must be full-bleed, so they drop the background's rounded corners.
+func rasterizeIcons(source []byte) (map[string][]byte, error) {
+	icons := map[string][]byte{}
+	for _, icon := range appIcons {
+		svg := string(source)
+		if icon.maskable {
+			svg = strings.Replace(svg, `rx="96" ry="96"`, `rx="0" ry="0"`, 1)
+		}
+		content, err := rasterizeSVG(svg, icon.size)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", icon.name, err)
+		}
+		icons[icon.name] = content
+	}
+	return icons, nil
+}
+
+// rasterizeSVG renders svg at its native size and resamples it to size.
+// oksvg doesn't scale stroke widths with the target, so it can't draw at size directly.
+func rasterizeSVG(svg string, size int) ([]byte, error) {
+	parsed, err := oksvg.ReadIconStream(strings.NewReader(svg), oksvg.StrictErrorMode)
+	if err != nil {
+		return nil, err
+	}
+	w, h := int(parsed.ViewBox.W), int(parsed.ViewBox.H)
+	parsed.SetTarget(0, 0, float64(w), float64(h))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	scanner := rasterx.NewScannerGV(w, h, img, img.Bounds())
+	parsed.Draw(rasterx.NewDasher(w, h, scanner), 1)
+
+	scaled := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Src, nil)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaled); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"embed"
 	"encoding/json"
 	"flag"
@@ -9,18 +9,17 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"net/smtp"
 	"os"
 	"strconv"
-	"strings"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"testserver/templates"
 )
 
-//go:embed data/*.json static/* manifest.json sw.js
+//go:embed data static/* sw.js.tmpl
 var embeddedFS embed.FS
 
 type GitHubRepo struct {
@@ -45,6 +44,19 @@ func detectLanguage(r *http.Request) string {
 func main() {
 	// Parse command-line flags
 	port := flag.String("p", "33333", "Port to run the server on")
+	useCDN := flag.Bool("cdn", false, "Load htmx and animate.css from their CDNs instead of self-hosting them")
+	flag.StringVar(&publicBaseURL, "base-url", "", "Public base URL of the site, e.g. https://example.com (defaults to the request host)")
+	flag.StringVar(&robotsFile, "robots", "", "Path to a robots.txt to serve instead of the generated one")
+	apUser := flag.String("ap-user", "", "Username for the ActivityPub actor and WebFinger (acct:user@domain); empty disables them")
+	apKey := flag.String("ap-key", "activitypub.pem", "Path to the ActivityPub actor's private key, generated on first run")
+	apState := flag.String("ap-state", "activitypub.json", "Path to the file storing ActivityPub followers and announcements")
+	mentionState := flag.String("webmention-state", "webmentions.json", "Path to the file storing received webmentions")
+	sshAddr := flag.String("ssh", "", "Address for the read-only SSH CV, e.g. :2222; empty disables it")
+	sshHostKey := flag.String("ssh-host-key", "ssh_host_ed25519_key", "Path to the SSH host key, generated on first run")
+	sshMaxPerIP := flag.Int("ssh-max-per-ip", 3, "Maximum concurrent SSH connections per IP")
+	geminiAddr := flag.String("gemini", "", "Address for the Gemini capsule, e.g. :1965; empty disables it")
+	geminiCert := flag.String("gemini-cert", "gemini.crt", "Path to the Gemini TLS certificate, self-signed on first run")
+	geminiKey := flag.String("gemini-key", "gemini.key", "Path to the Gemini TLS private key")
 	flag.Parse()
 
 	// Create a new Chi router
@@ -55,78 +67,110 @@ func main() {
 	router.Use(middleware.Compress(5)) // GZIP compression
 	router.Use(middleware.Recoverer)
 
-	// Static files with correct MIME types
-	router.HandleFunc("/static/*", func(w http.ResponseWriter, r *http.Request) {
-		path := strings.TrimPrefix(r.URL.Path, "/static/")
-		file, err := embeddedFS.Open("static/" + path)
-		if err != nil {
-			http.NotFound(w, r)
-			return
-		}
-		defer file.Close()
-		content, err := io.ReadAll(file)
-		if err != nil {
-			http.Error(w, "Error reading file", http.StatusInternalServerError)
-			return
-		}
-		reader := bytes.NewReader(content)
-		// Set Content-Type based on extension
-		if strings.HasSuffix(path, ".css") {
-			w.Header().Set("Content-Type", "text/css")
-		} else if strings.HasSuffix(path, ".js") {
-			w.Header().Set("Content-Type", "application/javascript")
-		}
-		http.ServeContent(w, r, path, time.Time{}, reader)
+	// Static files, fingerprinted and precompressed at startup
+	assets := mustLoadAssets()
+	templates.VendorAssets = vendorAssets(assets, *useCDN)
+	router.Handle("/static/*", assets)
+
+	// Web app manifest generated per language from the profile
+	router.Get("/manifest.json", serveManifest)
+
+	// Service worker generated from the asset manifest
+	router.Handle("/sw.js", mustNewServiceWorker(assets))
+
+	// Offline fallback page precached by the service worker
+	router.Get("/offline", func(w http.ResponseWriter, r *http.Request) {
+		lang := detectLanguage(r)
+		templates.OfflineTemplate(lang).Render(r.Context(), w)
 	})
 
-	router.HandleFunc("/manifest.json", func(w http.ResponseWriter, r *http.Request) {
-		file, err := embeddedFS.Open("manifest.json")
-		if err != nil {
-			http.NotFound(w, r)
-			return
+	// Search engine discovery
+	router.Get("/sitemap.xml", serveSitemap)
+	router.Get("/robots.txt", serveRobots)
+
+	// Fediverse discovery: WebFinger and a read-only ActivityPub actor
+	if *apUser != "" {
+		if publicBaseURL == "" {
+			log.Fatal("-ap-user requires -base-url")
 		}
-		defer file.Close()
-		content, err := io.ReadAll(file)
+		ap, err := newActivityPub(*apUser, publicBaseURL, *apKey, *apState)
 		if err != nil {
-			http.Error(w, "Error reading file", http.StatusInternalServerError)
-			return
+			log.Fatalf("Error setting up ActivityPub: %v", err)
 		}
-		reader := bytes.NewReader(content)
-		w.Header().Set("Content-Type", "application/json")
-		http.ServeContent(w, r, "manifest.json", time.Time{}, reader)
-	})
+		ap.Routes(router)
+		go ap.AnnounceNewProjects(context.Background())
+	}
+
+	// Webmentions, verified in the background and shown once approved
+	mentions, err := newWebmentionStore(*mentionState)
+	if err != nil {
+		log.Fatalf("Error loading webmentions: %v", err)
+	}
+	router.Post("/webmention", mentions.ServeHTTP)
+	mentions.Routes(router)
 
-	router.HandleFunc("/sw.js", func(w http.ResponseWriter, r *http.Request) {
-		file, err := embeddedFS.Open("sw.js")
+	// Interactive CV over SSH
+	if *sshAddr != "" {
+		sshServer, err := newSSHServer(*sshHostKey, *sshMaxPerIP)
 		if err != nil {
-			http.NotFound(w, r)
-			return
+			log.Fatalf("Error setting up SSH: %v", err)
+		}
+		go func() {
+			log.Fatal(sshServer.ListenAndServe(*sshAddr))
+		}()
+	}
+
+	// Gemini capsule, served for the -base-url host (or any host without it)
+	if *geminiAddr != "" {
+		host := ""
+		if u, err := url.Parse(publicBaseURL); err == nil {
+			host = u.Hostname()
 		}
-		defer file.Close()
-		content, err := io.ReadAll(file)
+		capsule, err := newGeminiServer(*geminiCert, *geminiKey, host)
 		if err != nil {
-			http.Error(w, "Error reading file", http.StatusInternalServerError)
-			return
+			log.Fatalf("Error setting up Gemini: %v", err)
 		}
-		reader := bytes.NewReader(content)
-		w.Header().Set("Content-Type", "application/javascript")
-		http.ServeContent(w, r, "sw.js", time.Time{}, reader)
-	})
+		go func() {
+			log.Fatal(capsule.ListenAndServe(*geminiAddr))
+		}()
+	}
+
+	// Open Graph cards, rendered on first request
+	router.Handle("/og/{lang}/{page}.png", mustNewOGImages())
 
 	// Handle root route
 	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		lang := detectLanguage(r)
 		data := templates.IndexData{
-			Skills: []string{
-				"Golang", "Python", "C", "React",
-				"Stripe", "HubSpot", "PostgreSQL", "Docker", "Kubernetes", "Git", "Agile Methodologies",
-				"Dutch (Native)", "English (Fluent)", "French (Fluent)",
-				"AI Integration", "Privacy-Conscious AI", "Event Sourcing", "Domain-Driven Design",
-			},
+			Skills:       loadSkillsData(lang),
 			Profile:      loadProfileData(lang),
+			Experience:   loadExperienceData(lang),
+			Education:    loadEducationData(lang),
+			Projects:     loadProjectsData(lang),
+			BaseURL:      baseURL(r),
 			Translations: templates.Translations,
 			Language:     lang,
 		}
+		// Sections are rendered inline; the /cv/* partials remain for htmx swaps.
+		data.Experience.Expand = r.URL.Query().Get("expand")
+		if skill, ok := findSkill(data.Skills, r.URL.Query().Get("skill")); ok {
+			filterExperience(&data.Experience, skill)
+		}
+		data.Experience.Language = lang
+		data.Experience.Translations = templates.Translations
+		data.Education.Language = lang
+		data.Education.Translations = templates.Translations
+		data.Projects.Mentions = mentions.Approved()
+		data.Projects.Language = lang
+		data.Projects.Translations = templates.Translations
+		data.Skills = filterSkills(data.Skills, r.URL.Query().Get("q"), r.URL.Query().Get("category"))
+		data.Skills.Language = lang
+		data.Skills.Translations = templates.Translations
+		if wantsText(r) {
+			serveTextCV(w, r, data)
+			return
+		}
+		w.Header().Add("Vary", "User-Agent, Accept")
 		templates.IndexTemplate(data).Render(r.Context(), w)
 	})
 
@@ -135,48 +179,23 @@ func main() {
 		lang := detectLanguage(r)
 		w.Header().Set("Content-Type", "text/html")
 		data := loadExperienceData(lang)
+		data.Expand = r.URL.Query().Get("expand")
+		if skill, ok := findSkill(loadSkillsData(lang), r.URL.Query().Get("skill")); ok {
+			filterExperience(&data, skill)
+		}
 		data.Language = lang
 		data.Translations = templates.Translations
 		templates.ExperienceTemplate(data).Render(r.Context(), w)
 	})
 
+	// Standalone case study pages
+	router.Get("/experience/{slug}", serveCaseStudy)
+
 	// New: Handle experience detail
-	router.Get("/cv/experience/detail/{id}", func(w http.ResponseWriter, r *http.Request) {
-		lang := detectLanguage(r)
-		idStr := chi.URLParam(r, "id")
-		id, err := strconv.Atoi(idStr)
-		if err != nil || id < 0 {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
-			return
-		}
-		w.Header().Set("Content-Type", "text/html")
-		data := loadExperienceData(lang)
-		if id >= len(data.ExperienceItems) {
-			http.Error(w, "ID out of range", http.StatusBadRequest)
-			return
-		}
-		item := data.ExperienceItems[id]
-		templates.ExperienceDetailTemplate(item, lang, id).Render(r.Context(), w)
-	})
+	router.Get("/cv/experience/detail/{id}", serveExperienceRole(true))
 
 	// New: Handle experience collapse
-	router.Get("/cv/experience/collapse/{id}", func(w http.ResponseWriter, r *http.Request) {
-		lang := detectLanguage(r)
-		idStr := chi.URLParam(r, "id")
-		id, err := strconv.Atoi(idStr)
-		if err != nil || id < 0 {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
-			return
-		}
-		w.Header().Set("Content-Type", "text/html")
-		data := loadExperienceData(lang)
-		if id >= len(data.ExperienceItems) {
-			http.Error(w, "ID out of range", http.StatusBadRequest)
-			return
-		}
-		item := data.ExperienceItems[id]
-		templates.ExperienceSummaryTemplate(item, id).Render(r.Context(), w)
-	})
+	router.Get("/cv/experience/collapse/{id}", serveExperienceRole(false))
 
 	// Handle education section
 	router.Get("/cv/education", func(w http.ResponseWriter, r *http.Request) {
@@ -193,12 +212,17 @@ func main() {
 		lang := detectLanguage(r)
 		w.Header().Set("Content-Type", "text/html")
 		data := loadProjectsData(lang)
+		data.Mentions = mentions.Approved()
 		data.Language = lang
 		data.Translations = templates.Translations
 		templates.ProjectsTemplate(data).Render(r.Context(), w)
 	})
 
 
+	// SVG charts for the skills and experience sections
+	router.Get("/cv/charts/skills.svg", serveSkillsChart)
+	router.Get("/cv/charts/timeline.svg", serveTimelineChart)
+
 	// New: Handle GitHub stats
 	router.Get("/api/github-stats/{repo}", func(w http.ResponseWriter, r *http.Request) {
 		repo := chi.URLParam(r, "repo")
@@ -221,23 +245,12 @@ func main() {
 
 	// New: Handle skills filter
 	router.Get("/cv/skills", func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query().Get("q")
-		allSkills := []string{
-			"Golang", "Python", "C", "React",
-			"Stripe", "HubSpot", "PostgreSQL", "Docker", "Kubernetes", "Git", "Agile Methodologies",
-			"Dutch (Native)", "English (Fluent)", "French (Fluent)",
-			"AI Integration", "Privacy-Conscious AI", "Event Sourcing", "Domain-Driven Design",
-		}
-		filtered := []string{}
-		for _, skill := range allSkills {
-			if strings.Contains(strings.ToLower(skill), strings.ToLower(query)) {
-				filtered = append(filtered, skill)
-			}
-		}
+		lang := detectLanguage(r)
 		w.Header().Set("Content-Type", "text/html")
-		for _, skill := range filtered {
-			fmt.Fprintf(w, `<span class="skill-tag animate__animated animate__fadeIn">%s</span>`, skill)
-		}
+		data := filterSkills(loadSkillsData(lang), r.URL.Query().Get("q"), r.URL.Query().Get("category"))
+		data.Language = lang
+		data.Translations = templates.Translations
+		templates.SkillsTemplate(data).Render(r.Context(), w)
 	})
 
 	// Create server
@@ -253,84 +266,86 @@ func main() {
 }
 
 func loadExperienceData(lang string) templates.ExperienceData {
-	filename := fmt.Sprintf("data/experience_%s.json", lang)
-	file, err := embeddedFS.Open(filename)
-	if err != nil {
-		log.Printf("Error loading %s: %v, falling back to English", filename, err)
-		filename = "data/experience_en.json"
-		file, err = embeddedFS.Open(filename)
-		if err != nil {
-			log.Printf("Error loading fallback %s: %v", filename, err)
-			return templates.ExperienceData{}
-		}
-	}
-	defer file.Close()
 	var data templates.ExperienceData
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		log.Printf("Error decoding %s: %v", filename, err)
+	filename, err := loadContent(embeddedFS, "experience", lang, &data)
+	if err != nil {
+		log.Printf("Error loading experience: %v", err)
 		return templates.ExperienceData{}
 	}
+	migrateExperience(&data, filename)
+	for i := range data.ExperienceItems {
+		data.ExperienceItems[i].CaseStudy = hasCaseStudy(data.ExperienceItems[i].Slug)
+	}
 	return data
 }
 
-func loadEducationData(lang string) templates.EducationData {
-	filename := fmt.Sprintf("data/education_%s.json", lang)
-	file, err := embeddedFS.Open(filename)
-	if err != nil {
-		log.Printf("Error loading %s: %v, falling back to English", filename, err)
-		filename = "data/education_en.json"
-		file, err = embeddedFS.Open(filename)
-		if err != nil {
-			log.Printf("Error loading fallback %s: %v", filename, err)
-			return templates.EducationData{}
+// serveExperienceRole renders one role expanded or collapsed for the disclosure
+// swap, filtered by the skill the timeline is filtered by so its bullets stay
+// highlighted
+func serveExperienceRole(expanded bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lang := detectLanguage(r)
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id < 0 {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		data := loadExperienceData(lang)
+		if id >= len(data.ExperienceItems) {
+			http.Error(w, "ID out of range", http.StatusBadRequest)
+			return
+		}
+		if skill, ok := findSkill(loadSkillsData(lang), r.URL.Query().Get("skill")); ok {
+			filterExperience(&data, skill)
+		}
+		item := data.ExperienceItems[id]
+		if expanded {
+			templates.ExperienceDetailTemplate(item, lang, data.Skill, id).Render(r.Context(), w)
+		} else {
+			templates.ExperienceSummaryTemplate(item, lang, data.Skill, id).Render(r.Context(), w)
 		}
 	}
-	defer file.Close()
+}
+
+func loadEducationData(lang string) templates.EducationData {
 	var data templates.EducationData
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		log.Printf("Error decoding %s: %v", filename, err)
+	filename, err := loadContent(embeddedFS, "education", lang, &data)
+	if err != nil {
+		log.Printf("Error loading education: %v", err)
 		return templates.EducationData{}
 	}
+	migrateEducation(&data, filename)
 	return data
 }
 
 func loadProjectsData(lang string) templates.ProjectsData {
-	filename := fmt.Sprintf("data/projects_%s.json", lang)
-	file, err := embeddedFS.Open(filename)
-	if err != nil {
-		log.Printf("Error loading %s: %v, falling back to English", filename, err)
-		filename = "data/projects_en.json"
-		file, err = embeddedFS.Open(filename)
-		if err != nil {
-			log.Printf("Error loading fallback %s: %v", filename, err)
-			return templates.ProjectsData{}
-		}
-	}
-	defer file.Close()
 	var data templates.ProjectsData
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		log.Printf("Error decoding %s: %v", filename, err)
+	_, err := loadContent(embeddedFS, "projects", lang, &data)
+	if err != nil {
+		log.Printf("Error loading projects: %v", err)
 		return templates.ProjectsData{}
 	}
 	return data
 }
 
-func loadProfileData(lang string) templates.ProfileData {
-	filename := fmt.Sprintf("data/profile_%s.json", lang)
-	file, err := embeddedFS.Open(filename)
+func loadSkillsData(lang string) templates.SkillsData {
+	var data templates.SkillsData
+	_, err := loadContent(embeddedFS, "skills", lang, &data)
 	if err != nil {
-		log.Printf("Error loading %s: %v, falling back to English", filename, err)
-		filename = "data/profile_en.json"
-		file, err = embeddedFS.Open(filename)
-		if err != nil {
-			log.Printf("Error loading fallback %s: %v", filename, err)
-			return templates.ProfileData{}
-		}
+		log.Printf("Error loading skills: %v", err)
+		return templates.SkillsData{}
 	}
-	defer file.Close()
+	annotateSkills(&data, loadExperienceData(lang), loadProjectsData(lang), lang)
+	return data
+}
+
+func loadProfileData(lang string) templates.ProfileData {
 	var data templates.ProfileData
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		log.Printf("Error decoding %s: %v", filename, err)
+	_, err := loadContent(embeddedFS, "profile", lang, &data)
+	if err != nil {
+		log.Printf("Error loading profile: %v", err)
 		return templates.ProfileData{}
 	}
 	return data
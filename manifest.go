@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"testserver/templates"
+)
+
+type manifestIcon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes"`
+	Type    string `json:"type"`
+	Purpose string `json:"purpose,omitempty"`
+}
+
+type manifestShortcut struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type webManifest struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	ShortName       string             `json:"short_name"`
+	Description     string             `json:"description"`
+	Lang            string             `json:"lang"`
+	Dir             string             `json:"dir"`
+	StartURL        string             `json:"start_url"`
+	Scope           string             `json:"scope"`
+	Display         string             `json:"display"`
+	BackgroundColor string             `json:"background_color"`
+	ThemeColor      string             `json:"theme_color"`
+	Icons           []manifestIcon     `json:"icons"`
+	Shortcuts       []manifestShortcut `json:"shortcuts"`
+}
+
+// Sections offered as app shortcuts, with the translation key of their label
+var manifestSections = []struct{ anchor, labelKey string }{
+	{"experience", "experience"},
+	{"education", "education"},
+	{"projects", "projects"},
+	{"skills", "skills"},
+	{"contact", "contact"},
+}
+
+func buildManifest(lang string, profile templates.ProfileData) webManifest {
+	manifest := webManifest{
+		ID:              "/",
+		Name:            profile.Name + " - " + profile.JobTitle,
+		ShortName:       profile.Name,
+		Description:     templates.PlainText(profile.Text),
+		Lang:            lang,
+		Dir:             "ltr", // Both supported languages are left-to-right
+		StartURL:        "/?lang=" + lang,
+		Scope:           "/",
+		Display:         "standalone",
+		BackgroundColor: "#4F46E5",
+		ThemeColor:      "#EC4899",
+		Icons: []manifestIcon{
+			{Src: templates.Asset("icon-192.png"), Sizes: "192x192", Type: "image/png"},
+			{Src: templates.Asset("icon-512.png"), Sizes: "512x512", Type: "image/png"},
+			{Src: templates.Asset("icon-maskable-512.png"), Sizes: "512x512", Type: "image/png", Purpose: "maskable"},
+			{Src: templates.Asset("icon.svg"), Sizes: "any", Type: "image/svg+xml"},
+		},
+	}
+	for _, section := range manifestSections {
+		manifest.Shortcuts = append(manifest.Shortcuts, manifestShortcut{
+			Name: templates.GetTranslation(section.labelKey, lang),
+			URL:  "/?lang=" + lang + "#" + section.anchor,
+		})
+	}
+	return manifest
+}
+
+func serveManifest(w http.ResponseWriter, r *http.Request) {
+	lang := detectLanguage(r)
+	w.Header().Set("Content-Type", "application/manifest+json")
+	w.Header().Set("Vary", "Cookie")
+	json.NewEncoder(w).Encode(buildManifest(lang, loadProfileData(lang)))
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"testserver/templates"
+)
+
+const (
+	ogWidth   = 1200
+	ogHeight  = 630
+	ogPadding = 80
+)
+
+// Pages with an Open Graph card besides projects, mapped to the translation key of their title
+var ogSections = map[string]string{
+	"home":       "",
+	"experience": "professional_experience",
+	"education":  "education",
+	"projects":   "personal_projects",
+	"skills":     "skills",
+	"contact":    "contact_me",
+}
+
+var (
+	ogStart = color.RGBA{0x4F, 0x46, 0xE5, 0xFF} // indigo-600
+	ogEnd   = color.RGBA{0xEC, 0x48, 0x99, 0xFF} // pink-500
+	ogMuted = color.RGBA{0xFC, 0xE7, 0xF3, 0xFF} // pink-100
+)
+
+// ogImages renders Open Graph cards on first request and keeps them in memory.
+// Font faces are not safe for concurrent use, so rendering happens with mu held.
+type ogImages struct {
+	mu     sync.Mutex
+	images map[string][]byte
+
+	nameFace, headlineFace, titleFace font.Face
+}
+
+func newOGImages() (*ogImages, error) {
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	og := &ogImages{images: map[string][]byte{}}
+	if og.nameFace, err = newFace(bold, 80); err != nil {
+		return nil, err
+	}
+	if og.headlineFace, err = newFace(regular, 36); err != nil {
+		return nil, err
+	}
+	if og.titleFace, err = newFace(bold, 52); err != nil {
+		return nil, err
+	}
+	return og, nil
+}
+
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// ogPageTitle returns the section title, role or project name shown on the card for page
+func ogPageTitle(lang, page string) (string, bool) {
+	if key, ok := ogSections[page]; ok {
+		if key == "" {
+			return "", true
+		}
+		return templates.GetTranslation(key, lang), true
+	}
+	for _, item := range loadExperienceData(lang).ExperienceItems {
+		if templates.RoleOGPage(item.Slug) == page {
+			return item.Title + " · " + item.Company, true
+		}
+	}
+	for _, item := range loadProjectsData(lang).ProjectItems {
+		if item.Slug == page {
+			return item.Title, true
+		}
+	}
+	return "", false
+}
+
+func (og *ogImages) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	lang := chi.URLParam(r, "lang")
+	page := chi.URLParam(r, "page")
+	if !slices.Contains(templates.Languages, lang) {
+		http.NotFound(w, r)
+		return
+	}
+	title, ok := ogPageTitle(lang, page)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	key := lang + "/" + page
+	og.mu.Lock()
+	content, ok := og.images[key]
+	if !ok {
+		var err error
+		content, err = og.render(loadProfileData(lang), title)
+		if err != nil {
+			og.mu.Unlock()
+			log.Printf("Error rendering Open Graph image %s: %v", key, err)
+			http.Error(w, "Error rendering image", http.StatusInternalServerError)
+			return
+		}
+		og.images[key] = content
+	}
+	og.mu.Unlock()
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(content)
+}
+
+// render draws a branded card with the name, headline and page title
+func (og *ogImages) render(profile templates.ProfileData, title string) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, ogWidth, ogHeight))
+	for y := 0; y < ogHeight; y++ {
+		for x := 0; x < ogWidth; x++ {
+			t := float64(x+y) / float64(ogWidth+ogHeight)
+			img.SetRGBA(x, y, color.RGBA{
+				R: lerp(ogStart.R, ogEnd.R, t),
+				G: lerp(ogStart.G, ogEnd.G, t),
+				B: lerp(ogStart.B, ogEnd.B, t),
+				A: 0xFF,
+			})
+		}
+	}
+
+	maxWidth := ogWidth - 2*ogPadding
+	y := ogPadding + og.nameFace.Metrics().Ascent.Ceil()
+	drawText(img, og.nameFace, color.White, ogPadding, y, profile.Name)
+	y += og.nameFace.Metrics().Height.Ceil() / 2
+	for _, line := range wrapText(og.headlineFace, profile.Headline, maxWidth) {
+		y += og.headlineFace.Metrics().Height.Ceil()
+		drawText(img, og.headlineFace, ogMuted, ogPadding, y, line)
+	}
+
+	if title != "" {
+		lines := wrapText(og.titleFace, title, maxWidth)
+		lineHeight := og.titleFace.Metrics().Height.Ceil()
+		y = ogHeight - ogPadding - (len(lines)-1)*lineHeight
+		// Accent bar above the title
+		bar := image.Rect(ogPadding, y-lineHeight-24, ogPadding+120, y-lineHeight-16)
+		for py := bar.Min.Y; py < bar.Max.Y; py++ {
+			for px := bar.Min.X; px < bar.Max.X; px++ {
+				img.Set(px, py, color.White)
+			}
+		}
+		for _, line := range lines {
+			drawText(img, og.titleFace, color.White, ogPadding, y, line)
+			y += lineHeight
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func lerp(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t)
+}
+
+func drawText(img *image.RGBA, face font.Face, c color.Color, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// wrapText splits text into lines no wider than maxWidth pixels
+func wrapText(face font.Face, text string, maxWidth int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && font.MeasureString(face, candidate).Ceil() > maxWidth {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func mustNewOGImages() *ogImages {
+	og, err := newOGImages()
+	if err != nil {
+		log.Fatalf("Error loading Open Graph fonts: %v", err)
+	}
+	return og
+}
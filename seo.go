@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"testserver/templates"
+)
+
+// Public base URL of the site, set with -base-url
+var publicBaseURL string
+
+// Robots.txt served instead of the generated one, set with -robots
+var robotsFile string
+
+var startTime = time.Now()
+
+// baseURL returns the configured public base URL, or one derived from the request
+func baseURL(r *http.Request) string {
+	if publicBaseURL != "" {
+		return strings.TrimSuffix(publicBaseURL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// A public page and the content files it is rendered from, per language
+type sitePage struct {
+	path         string
+	languages    []string // Languages the page is available in; all when nil
+	contentFiles func(lang string) []string
+}
+
+func (p sitePage) pageLanguages() []string {
+	if p.languages == nil {
+		return templates.Languages
+	}
+	return p.languages
+}
+
+var sitePages = []sitePage{
+	{
+		path: "/",
+		contentFiles: func(lang string) []string {
+			files := []string{}
+			for _, kind := range []string{"profile", "experience", "education", "projects", "skills"} {
+				files = append(files, contentFiles(embeddedFS, kind, lang)...)
+			}
+			return files
+		},
+	},
+}
+
+// pageURL is the canonical URL of path in lang
+func pageURL(base, path, lang string) string {
+	return base + path + "?lang=" + lang
+}
+
+// contentLastMod returns when the newest of files last changed. Embedded files carry
+// no modification time, so it uses the file on disk when running from the source tree
+// and falls back to the commit time of the build.
+func contentLastMod(files []string) time.Time {
+	var latest time.Time
+	for _, name := range files {
+		if info, err := os.Stat(name); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	if !latest.IsZero() {
+		return latest
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.time" {
+				if t, err := time.Parse(time.RFC3339, setting.Value); err == nil {
+					return t
+				}
+			}
+		}
+	}
+	return startTime
+}
+
+type sitemapLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type sitemapURL struct {
+	Loc     string        `xml:"loc"`
+	LastMod string        `xml:"lastmod"`
+	Links   []sitemapLink `xml:"xhtml:link"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Xhtml   string       `xml:"xmlns:xhtml,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+func serveSitemap(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(r)
+	urlset := sitemapURLSet{
+		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		Xhtml: "http://www.w3.org/1999/xhtml",
+	}
+	for _, page := range sitePages {
+		var alternates []sitemapLink
+		for _, lang := range page.pageLanguages() {
+			alternates = append(alternates, sitemapLink{Rel: "alternate", Hreflang: lang, Href: pageURL(base, page.path, lang)})
+		}
+		alternates = append(alternates, sitemapLink{Rel: "alternate", Hreflang: "x-default", Href: base + page.path})
+		for _, lang := range page.pageLanguages() {
+			urlset.URLs = append(urlset.URLs, sitemapURL{
+				Loc:     pageURL(base, page.path, lang),
+				LastMod: contentLastMod(page.contentFiles(lang)).UTC().Format(time.RFC3339),
+				Links:   alternates,
+			})
+		}
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	fmt.Fprint(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	enc.Encode(urlset)
+}
+
+func serveRobots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if robotsFile != "" {
+		content, err := os.ReadFile(robotsFile)
+		if err != nil {
+			http.Error(w, "Error reading robots.txt", http.StatusInternalServerError)
+			return
+		}
+		w.Write(content)
+		return
+	}
+	fmt.Fprintf(w, "User-agent: *\nAllow: /\nDisallow: /api/\nAllow: /cv/charts/\nDisallow: /cv/\nDisallow: /offline\n\nSitemap: %s/sitemap.xml\n", baseURL(r))
+}
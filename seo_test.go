@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRobotsAllowsCharts(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serveRobots(rec, httptest.NewRequest(http.MethodGet, "https://ada.example/robots.txt", nil))
+	body := rec.Body.String()
+	allow, disallow := strings.Index(body, "Allow: /cv/charts/\n"), strings.Index(body, "Disallow: /cv/\n")
+	if allow < 0 || disallow < 0 || allow > disallow {
+		t.Errorf("robots.txt must allow /cv/charts/ before disallowing /cv/:\n%s", body)
+	}
+}
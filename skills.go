@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"testserver/templates"
+)
+
+// Groups of interchangeable names; a skill matches a query for any name in a group
+// that shares a word with the skill. Entries are in normalized form.
+var skillAliases = [][]string{
+	{"golang", "go"},
+	{"kubernetes", "k8s"},
+	{"postgresql", "postgres", "psql"},
+	{"domain-driven design", "ddd"},
+	{"agile methodologies", "agile", "scrum"},
+	{"ai", "ia", "artificial intelligence", "intelligence artificielle"},
+	{"dutch", "neerlandais", "nederlands"},
+	{"english", "anglais"},
+	{"french", "francais"},
+}
+
+// normalizeSkill lowercases s and strips accents, so "Français" becomes "francais"
+func normalizeSkill(s string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		stripped = s
+	}
+	return strings.ToLower(strings.TrimSpace(stripped))
+}
+
+// skillTerms returns the normalized names a skill can be found under
+func skillTerms(skill string) []string {
+	name := normalizeSkill(skill)
+	keys := map[string]bool{name: true}
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		keys[word] = true
+	}
+	terms := []string{name}
+	for _, group := range skillAliases {
+		for _, alias := range group {
+			if keys[alias] {
+				terms = append(terms, group...)
+				break
+			}
+		}
+	}
+	return terms
+}
+
+// filterSkills keeps the skills in category (all when empty or unknown) matching
+// query, ignoring case and accents and following aliases such as "k8s" for Kubernetes
+func filterSkills(data templates.SkillsData, query, category string) templates.SkillsData {
+	known := false
+	for _, c := range data.Categories {
+		known = known || c.Slug == category
+	}
+	if known {
+		data.Category = category
+	}
+	data.Query = query
+	data.Available = data.Categories
+	query = normalizeSkill(query)
+
+	categories := []templates.SkillCategory{}
+	for _, c := range data.Categories {
+		if data.Category != "" && c.Slug != data.Category {
+			continue
+		}
+		skills := []templates.SkillItem{}
+		for _, skill := range c.Skills {
+			if skillMatches(skill, query) {
+				skills = append(skills, skill)
+			}
+		}
+		if len(skills) > 0 {
+			c.Skills = skills
+			categories = append(categories, c)
+		}
+	}
+	data.Categories = categories
+	return data
+}
+
+// skillMatches reports whether query is part of the skill's name, or whole words of
+// its slug or one of its aliases, so "art" doesn't find AI through "artificial
+// intelligence" but "intelligence" does
+func skillMatches(skill templates.SkillItem, query string) bool {
+	if query == "" || strings.Contains(normalizeSkill(skill.Name), query) {
+		return true
+	}
+	for _, term := range append(skillTerms(skill.Name), skillTerms(skill.Slug)...) {
+		if strings.Contains(" "+skillWords(term)+" ", " "+skillWords(query)+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// skillWords joins the words of a normalized term with single spaces
+func skillWords(term string) string {
+	return strings.Join(strings.FieldsFunc(term, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }), " ")
+}
+
+// annotateSkills fills in where each skill was used and, unless set by hand, the
+// years of experience covered by its roles (overlapping roles count once)
+func annotateSkills(data *templates.SkillsData, experience templates.ExperienceData, projects templates.ProjectsData, lang string) {
+	for c := range data.Categories {
+		for s := range data.Categories[c].Skills {
+			skill := &data.Categories[c].Skills[s]
+			months := map[int]bool{}
+			for i, item := range experience.ExperienceItems {
+				if !slices.Contains(item.Skills, skill.Slug) {
+					continue
+				}
+				skill.UsedAt = append(skill.UsedAt, templates.SkillUse{
+					Title:  item.Title,
+					Detail: item.Company + " · " + item.PeriodLabel(lang),
+					URL:    fmt.Sprintf("/?lang=%s&expand=%s#summary-%d", lang, item.Slug, i),
+				})
+				if item.Start.IsZero() {
+					continue // Dates that couldn't be parsed don't count towards years
+				}
+				for month := range templates.MonthsBetween(item.Start, item.End, time.Now()) {
+					months[item.Start.Index()+month] = true
+				}
+			}
+			for _, item := range projects.ProjectItems {
+				if slices.Contains(item.Skills, skill.Slug) {
+					skill.Projects = append(skill.Projects, templates.SkillUse{
+						Title: item.Title,
+						URL:   fmt.Sprintf("/?lang=%s#project-%s", lang, item.Slug),
+					})
+				}
+			}
+			if skill.Years == 0 {
+				skill.Years = len(months) / 12
+			}
+		}
+	}
+}
+
+// findSkill resolves a skill from its slug, name or one of its aliases
+func findSkill(data templates.SkillsData, query string) (templates.SkillItem, bool) {
+	query = normalizeSkill(query)
+	for _, category := range data.Categories {
+		for _, skill := range category.Skills {
+			if skill.Slug == query || slices.Contains(skillTerms(skill.Name), query) {
+				return skill, true
+			}
+		}
+	}
+	return templates.SkillItem{}, false
+}
+
+// mentionsSkill reports whether text names the skill under one of its terms,
+// matching single-word terms on whole words so "go" doesn't match "going"
+func mentionsSkill(text string, terms []string) bool {
+	text = normalizeSkill(text)
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		words[word] = true
+	}
+	for _, term := range terms {
+		if words[term] || strings.ContainsAny(term, " -") && strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterExperience narrows data to the roles tagged with skill and marks the
+// description bullets that mention it
+func filterExperience(data *templates.ExperienceData, skill templates.SkillItem) {
+	data.Skill, data.SkillName = skill.Slug, skill.Name
+	terms := skillTerms(skill.Name)
+	for i := range data.ExperienceItems {
+		item := &data.ExperienceItems[i]
+		if !slices.Contains(item.Skills, skill.Slug) {
+			continue
+		}
+		item.Highlighted = make([]bool, len(item.Description))
+		for j, desc := range item.Description {
+			item.Highlighted[j] = mentionsSkill(desc.String(), terms)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"testserver/templates"
+)
+
+func TestAnnotateSkillsSkipsUnparsedDates(t *testing.T) {
+	end := templates.Month{Year: 2020, Month: 1}
+	skills := templates.SkillsData{Categories: []templates.SkillCategory{
+		{Slug: "programming", Skills: []templates.SkillItem{{Slug: "go", Name: "Go"}}},
+	}}
+	experience := templates.ExperienceData{ExperienceItems: []templates.ExperienceItem{
+		{Slug: "dated", Title: "Developer", Start: templates.Month{Year: 2018, Month: 1}, End: &end, Skills: []string{"go"}},
+		{Slug: "undated", Title: "Consultant", Period: "A while", Skills: []string{"go"}},
+	}}
+	annotateSkills(&skills, experience, templates.ProjectsData{}, "en")
+
+	skill := skills.Categories[0].Skills[0]
+	if skill.Years != 2 {
+		t.Errorf("Years = %d, want 2 from the dated role only", skill.Years)
+	}
+	if len(skill.UsedAt) != 2 || skill.UsedAt[1].Detail != " · A while" {
+		t.Errorf("UsedAt = %+v, want both roles", skill.UsedAt)
+	}
+}
+
+func TestSkillMatches(t *testing.T) {
+	ai := templates.SkillItem{Slug: "ai", Name: "AI"}
+	golang := templates.SkillItem{Slug: "golang", Name: "Go"}
+	k8s := templates.SkillItem{Slug: "kubernetes", Name: "Kubernetes"}
+	ddd := templates.SkillItem{Slug: "ddd", Name: "Domain-Driven Design"}
+	tests := []struct {
+		skill templates.SkillItem
+		query string
+		want  bool
+	}{
+		{ai, "art", false},
+		{ai, "artificial", true},
+		{ai, "intelligence", true},
+		{ai, "artificial intelligence", true},
+		{ai, "Intelligence Artificielle", true},
+		{golang, "go", true},
+		{golang, "golang", true},
+		{golang, "lang", false},
+		{k8s, "kube", true},
+		{k8s, "k8s", true},
+		{k8s, "8s", false},
+		{ddd, "driven", true},
+		{ddd, "ddd", true},
+		{ddd, "dd", false},
+	}
+	for _, tt := range tests {
+		if got := skillMatches(tt.skill, normalizeSkill(tt.query)); got != tt.want {
+			t.Errorf("skillMatches(%s, %q) = %v, want %v", tt.skill.Slug, tt.query, got, tt.want)
+		}
+	}
+}
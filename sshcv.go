@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"testserver/templates"
+)
+
+// Read-only SSH access to the CV: any user can log in without credentials and gets
+// a small TUI; shells, exec, subsystems and port forwarding are refused.
+
+const (
+	sshHandshakeTimeout = 10 * time.Second
+	sshIdleTimeout      = 10 * time.Minute
+	sshMaxSessions      = 2 // Concurrent sessions per connection
+)
+
+type sshServer struct {
+	config   *ssh.ServerConfig
+	maxPerIP int
+
+	mu    sync.Mutex
+	conns map[string]int
+}
+
+func newSSHServer(hostKeyPath string, maxPerIP int) (*sshServer, error) {
+	signer, err := loadOrCreateHostKey(hostKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true, ServerVersion: "SSH-2.0-cv"}
+	config.AddHostKey(signer)
+	return &sshServer{config: config, maxPerIP: maxPerIP, conns: map[string]int{}}, nil
+}
+
+// loadOrCreateHostKey reads an OpenSSH private key, generating an ed25519 one on first run
+func loadOrCreateHostKey(path string) (ssh.Signer, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		return ssh.ParsePrivateKey(content)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(key, "cv host key")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, err
+	}
+	log.Printf("Generated SSH host key %s", path)
+	return ssh.NewSignerFromKey(key)
+}
+
+// acquire counts a connection from ip, refusing it past the per-IP limit
+func (s *sshServer) acquire(ip string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns[ip] >= s.maxPerIP {
+		return false
+	}
+	s.conns[ip]++
+	return true
+}
+
+func (s *sshServer) release(ip string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns[ip]--; s.conns[ip] <= 0 {
+		delete(s.conns, ip)
+	}
+}
+
+func (s *sshServer) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("SSH CV listening on %s", addr)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handleConn(conn)
+	}
+}
+
+// idleConn closes connections that stay silent for sshIdleTimeout
+type idleConn struct {
+	net.Conn
+}
+
+func (c idleConn) Read(p []byte) (int, error) {
+	c.SetReadDeadline(time.Now().Add(sshIdleTimeout))
+	return c.Conn.Read(p)
+}
+
+func (s *sshServer) handleConn(conn net.Conn) {
+	defer conn.Close()
+	ip, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	if !s.acquire(ip) {
+		log.Printf("SSH: too many connections from %s", ip)
+		return
+	}
+	defer s.release(ip)
+
+	timeout := time.AfterFunc(sshHandshakeTimeout, func() { conn.Close() })
+	sshConn, channels, requests, err := ssh.NewServerConn(idleConn{conn}, s.config)
+	timeout.Stop()
+	if err != nil {
+		return
+	}
+	defer sshConn.Close()
+
+	go ssh.DiscardRequests(requests)
+	sessions := make(chan struct{}, sshMaxSessions)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.Prohibited, "only interactive sessions are supported")
+			continue
+		}
+		select {
+		case sessions <- struct{}{}:
+		default:
+			newChannel.Reject(ssh.ResourceShortage, "too many sessions on this connection")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			<-sessions
+			continue
+		}
+		go func() {
+			defer func() { <-sessions }()
+			handleSession(channel, requests)
+		}()
+	}
+}
+
+// handleSession answers session requests: a pty and a shell start the TUI,
+// a shell without a pty gets the plain text CV
+func handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	session := &cvSession{channel: channel, lang: "en", width: defaultTextWidth, height: 24, expanded: map[int]bool{}}
+	started := false
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			session.resize(parsePtyRequest(req.Payload))
+			session.pty = true
+			req.Reply(true, nil)
+		case "window-change":
+			session.resize(parseWindowChange(req.Payload))
+			if started {
+				session.render()
+			}
+		case "shell":
+			if started {
+				req.Reply(false, nil)
+				continue
+			}
+			started = true
+			req.Reply(true, nil)
+			go func() {
+				session.run()
+				channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+				channel.Close()
+			}()
+		default:
+			// exec, subsystem, env, x11 and agent forwarding are refused
+			req.Reply(false, nil)
+		}
+	}
+}
+
+func parsePtyRequest(payload []byte) (int, int) {
+	var pty struct {
+		Term          string
+		Columns, Rows uint32
+		Width, Height uint32
+		Modes         string
+	}
+	if ssh.Unmarshal(payload, &pty) != nil {
+		return 0, 0
+	}
+	return int(pty.Columns), int(pty.Rows)
+}
+
+func parseWindowChange(payload []byte) (int, int) {
+	if len(payload) < 8 {
+		return 0, 0
+	}
+	return int(binary.BigEndian.Uint32(payload)), int(binary.BigEndian.Uint32(payload[4:]))
+}
+
+// TUI sections, in tab order
+var sshSections = []string{"professional_experience", "education", "personal_projects", "skills"}
+
+type cvSession struct {
+	channel ssh.Channel
+	pty     bool
+
+	mu            sync.Mutex
+	width, height int
+	lang          string
+	section       int
+	cursor        int // Selected experience item
+	expanded      map[int]bool
+	scroll        int
+}
+
+func (s *cvSession) resize(width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if width > 0 && height > 0 {
+		s.width, s.height = width, height
+	}
+}
+
+func (s *cvSession) data() templates.IndexData {
+	return templates.IndexData{
+		Skills:     loadSkillsData(s.lang),
+		Profile:    loadProfileData(s.lang),
+		Experience: loadExperienceData(s.lang),
+		Education:  loadEducationData(s.lang),
+		Projects:   loadProjectsData(s.lang),
+		Language:   s.lang,
+	}
+}
+
+// run reads keys until the user quits or disconnects
+func (s *cvSession) run() {
+	if !s.pty {
+		var buf bytes.Buffer
+		data := s.data()
+		t := &textWriter{w: &buf, width: defaultTextWidth}
+		t.heading(templates.GetTranslation("professional_experience", s.lang))
+		for _, item := range data.Experience.ExperienceItems {
+			t.experienceItem(item, true, s.lang)
+		}
+		t.heading(templates.GetTranslation("education", s.lang))
+		t.education(data.Education.EducationItems, s.lang)
+		t.heading(templates.GetTranslation("personal_projects", s.lang))
+		t.projects(data.Projects.ProjectItems)
+		t.heading(templates.GetTranslation("skills", s.lang))
+		t.skills(data.Skills, s.lang)
+		s.channel.Write(buf.Bytes())
+		return
+	}
+
+	// Alternate screen, hidden cursor
+	s.channel.Write([]byte("\x1b[?1049h\x1b[?25l"))
+	defer s.channel.Write([]byte("\x1b[?25h\x1b[?1049l"))
+	s.render()
+	buf := make([]byte, 64)
+	for {
+		n, err := s.channel.Read(buf)
+		if err != nil {
+			return
+		}
+		for keys := string(buf[:n]); keys != ""; {
+			var key string
+			switch {
+			case strings.HasPrefix(keys, "\x1b[") && len(keys) >= 3:
+				key, keys = keys[:3], keys[3:]
+			default:
+				key, keys = keys[:1], keys[1:]
+			}
+			if !s.handleKey(key) {
+				return
+			}
+		}
+		s.render()
+	}
+}
+
+// handleKey updates the view for one key press and returns false to quit
+func (s *cvSession) handleKey(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := len(loadExperienceData(s.lang).ExperienceItems)
+	switch key {
+	case "q", "\x03", "\x04":
+		return false
+	case "\t", "\x1b[C":
+		s.section, s.scroll = (s.section+1)%len(sshSections), 0
+	case "\x1b[D":
+		s.section, s.scroll = (s.section+len(sshSections)-1)%len(sshSections), 0
+	case "1", "2", "3", "4":
+		s.section, s.scroll = int(key[0]-'1'), 0
+	case "l":
+		for i, lang := range templates.Languages {
+			if lang == s.lang {
+				s.lang = templates.Languages[(i+1)%len(templates.Languages)]
+				break
+			}
+		}
+	case "k", "\x1b[A":
+		if s.section == 0 {
+			s.cursor = max(s.cursor-1, 0)
+		} else {
+			s.scroll = max(s.scroll-1, 0)
+		}
+	case "j", "\x1b[B":
+		if s.section == 0 {
+			s.cursor = min(s.cursor+1, items-1)
+		} else {
+			s.scroll++
+		}
+	case "\r", " ":
+		if s.section == 0 {
+			s.expanded[s.cursor] = !s.expanded[s.cursor]
+		}
+	}
+	return true
+}
+
+// render redraws the whole screen
+func (s *cvSession) render() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := s.data()
+	width := max(minTextWidth, min(s.width-2, maxTextWidth))
+
+	var body bytes.Buffer
+	t := &textWriter{w: &body, width: width, color: true}
+	cursorLine := -1
+	switch s.section {
+	case 0:
+		for i, item := range data.Experience.ExperienceItems {
+			if i == s.cursor {
+				cursorLine = strings.Count(body.String(), "\n")
+				fmt.Fprint(&body, t.style("35", "▸ "))
+			}
+			t.experienceItem(item, s.expanded[i], s.lang)
+		}
+	case 1:
+		t.education(data.Education.EducationItems, s.lang)
+	case 2:
+		t.projects(data.Projects.ProjectItems)
+	case 3:
+		t.skills(data.Skills, s.lang)
+	}
+	lines := strings.Split(strings.TrimRight(body.String(), "\n"), "\n")
+
+	// Keep the selected item in view and the scroll inside the content
+	visible := max(s.height-6, 1)
+	if cursorLine >= 0 {
+		if cursorLine < s.scroll {
+			s.scroll = cursorLine
+		} else if cursorLine >= s.scroll+visible {
+			s.scroll = cursorLine - visible + 3
+		}
+	}
+	s.scroll = max(0, min(s.scroll, len(lines)-visible))
+	lines = lines[s.scroll:min(len(lines), s.scroll+visible)]
+
+	var screen bytes.Buffer
+	screen.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&screen, " %s %s %s\r\n", t.style("1;36", data.Profile.Name), t.style("2", "·"), t.style("36", data.Profile.JobTitle))
+	screen.WriteString(" ")
+	for i, key := range sshSections {
+		label := fmt.Sprintf(" %d %s ", i+1, templates.GetTranslation(key, s.lang))
+		if i == s.section {
+			label = t.style("1;7;35", label)
+		}
+		screen.WriteString(label + " ")
+	}
+	screen.WriteString("\r\n\r\n")
+	for _, line := range lines {
+		screen.WriteString(" " + line + "\r\n")
+	}
+	fmt.Fprintf(&screen, "\x1b[%d;1H %s", s.height, t.style("2", templates.GetTranslation("ssh_help", s.lang)))
+	s.channel.Write(screen.Bytes())
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHSessionLimit(t *testing.T) {
+	server, err := newSSHServer(filepath.Join(t.TempDir(), "host_key"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			server.handleConn(conn)
+		}
+	}()
+
+	client, err := ssh.Dial("tcp", listener.Addr().String(), &ssh.ClientConfig{
+		User:            "guest",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	sessions := []*ssh.Session{}
+	for range sshMaxSessions {
+		session, err := client.NewSession()
+		if err != nil {
+			t.Fatalf("session %d: %v", len(sessions)+1, err)
+		}
+		sessions = append(sessions, session)
+	}
+	_, err = client.NewSession()
+	var rejected *ssh.OpenChannelError
+	if !errors.As(err, &rejected) || rejected.Reason != ssh.ResourceShortage {
+		t.Fatalf("session over the limit: %v, want a resource shortage", err)
+	}
+
+	// Closing a session frees its slot
+	sessions[0].Close()
+	for {
+		session, err := client.NewSession()
+		if err == nil {
+			session.Close()
+			break
+		}
+		if !errors.As(err, &rejected) || rejected.Reason != ssh.ResourceShortage {
+			t.Fatal(err)
+		}
+	}
+}
@@ -8,3 +8,8 @@ function setLanguage(lang) {
 	document.cookie = `language=${lang}; path=/; max-age=31536000`; // 1 year
 	window.location.search = `?lang=${lang}`;
 }
+
+// Offline support
+if ('serviceWorker' in navigator) {
+	navigator.serviceWorker.register('/sw.js');
+}
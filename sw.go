@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"text/template"
+	"time"
+
+	"testserver/templates"
+)
+
+// htmx fragments precached alongside each language's page
+var fragmentPaths = []string{"/cv/experience", "/cv/education", "/cv/projects"}
+
+type serviceWorker struct {
+	script  []byte
+	modTime time.Time
+}
+
+// buildVersion hashes the embedded content, the asset fingerprints and the VCS revision,
+// so any change to what the site serves yields a new service worker cache.
+func buildVersion(store *assetStore) string {
+	h := sha256.New()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+				h.Write([]byte(setting.Value))
+			}
+		}
+	}
+	fs.WalkDir(embeddedFS, "data", func(name string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			content, _ := fs.ReadFile(embeddedFS, name)
+			h.Write(content)
+		}
+		return nil
+	})
+	names := make([]string, 0, len(store.byName))
+	for name := range store.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h.Write([]byte(name + store.byName[name].hash))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// newServiceWorker renders sw.js.tmpl with the precache list for the current build
+func newServiceWorker(store *assetStore) (*serviceWorker, error) {
+	tmpl, err := template.New("sw.js.tmpl").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).ParseFS(embeddedFS, "sw.js.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	precache := []string{}
+	offline := map[string]string{}
+	for _, lang := range templates.Languages {
+		precache = append(precache, "/?lang="+lang, "/offline?lang="+lang, "/manifest.json?lang="+lang)
+		for _, fragment := range fragmentPaths {
+			precache = append(precache, fragment+"?lang="+lang)
+		}
+		for _, chart := range []string{"skills", "timeline"} {
+			precache = append(precache, templates.ChartURL(chart, lang, "light"), templates.ChartURL(chart, lang, "dark"))
+		}
+		offline[lang] = "/offline?lang=" + lang
+	}
+	for _, path := range store.Paths() {
+		precache = append(precache, path)
+	}
+	sort.Strings(precache)
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, map[string]any{
+		"Version":  buildVersion(store),
+		"Precache": precache,
+		"Offline":  offline,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &serviceWorker{script: buf.Bytes(), modTime: store.modTime}, nil
+}
+
+func (sw *serviceWorker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/javascript")
+	// Browsers must always revalidate the worker to pick up new cache versions
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "sw.js", sw.modTime, bytes.NewReader(sw.script))
+}
+
+func mustNewServiceWorker(store *assetStore) *serviceWorker {
+	sw, err := newServiceWorker(store)
+	if err != nil {
+		log.Fatalf("Error generating service worker: %v", err)
+	}
+	return sw
+}
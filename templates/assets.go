@@ -0,0 +1,38 @@
+package templates
+
+import "fmt"
+
+// Fingerprinted URLs for files in static/, filled in by the server at startup
+var AssetPaths = map[string]string{}
+
+// Helper to get the URL of a static asset
+func Asset(name string) string {
+	if val, ok := AssetPaths[name]; ok {
+		return val
+	}
+	return "/static/" + name // Fallback to the unversioned path
+}
+
+// A third-party script or stylesheet, self-hosted or loaded from its CDN
+type VendorAsset struct {
+	URL       string
+	Integrity string // Subresource Integrity hash
+}
+
+// Third-party frontend dependencies keyed by file name, filled in by the server at startup
+var VendorAssets = map[string]VendorAsset{}
+
+// Helper to get the URL of the Open Graph card for a page ("home", a section, a project slug or a RoleOGPage)
+func OGImage(lang, page string) string {
+	return "/og/" + lang + "/" + page + ".png"
+}
+
+// RoleOGPage is the Open Graph card page of the role with slug
+func RoleOGPage(slug string) string {
+	return "experience-" + slug
+}
+
+// ChartURL is the path of a server-rendered SVG chart for a language and theme
+func ChartURL(name, lang, theme string) string {
+	return fmt.Sprintf("/cv/charts/%s.svg?lang=%s&theme=%s", name, lang, theme)
+}
@@ -0,0 +1,40 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestCaseStudyAdvertisesWrittenLanguages(t *testing.T) {
+	data := testIndexData("en")
+	var page bytes.Buffer
+	err := CaseStudyTemplate(CaseStudyData{
+		Item:      data.Experience.ExperienceItems[0],
+		Body:      templ.Raw("<p>Case study</p>"),
+		Profile:   data.Profile,
+		BaseURL:   data.BaseURL,
+		Language:  "en",
+		Languages: []string{"en"},
+	}).Render(context.Background(), &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := page.String()
+	for _, want := range []string{
+		`<html lang="en">`,
+		`<link rel="canonical" href="https://ada.example/experience/cto-engines?lang=en">`,
+		`hreflang="en" href="https://ada.example/experience/cto-engines?lang=en"`,
+		`<meta property="og:image" content="https://ada.example/og/en/experience-cto-engines.png">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page lacks %s", want)
+		}
+	}
+	if strings.Contains(html, `hreflang="fr"`) {
+		t.Error("page advertises a French version that isn't written")
+	}
+}
@@ -8,7 +8,7 @@ package templates
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-func ContactTemplate(lang string, translations map[string]map[string]string) templ.Component {
+func ContactTemplate(profile ProfileData, lang string, translations map[string]map[string]string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -29,7 +29,69 @@ func ContactTemplate(lang string, translations map[string]map[string]string) tem
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"bg-white dark:bg-gray-800 p-8 rounded-lg shadow-lg\"><h3 class=\"text-2xl font-bold text-indigo-600 dark:text-pink-400 mb-4\">Contact Information</h3><p class=\"text-gray-700 dark:text-gray-200 mb-2\"><strong>Address:</strong> 2 avenue de Boran, 60260 Lamorlaye</p><p class=\"text-gray-700 dark:text-gray-200 mb-2\"><strong>Phone:</strong> +33 7 69 52 77 59</p><p class=\"text-gray-700 dark:text-gray-200 mb-2\"><strong>Email:</strong> <a href=\"mailto:beetswouter@gmail.com\" class=\"text-indigo-600 dark:text-pink-400 hover:underline\">beetswouter@gmail.com</a></p><p class=\"text-gray-700 dark:text-gray-200\"><strong>GitHub:</strong> <a href=\"https://github.com/wouterbeets\" target=\"_blank\" class=\"text-indigo-600 dark:text-pink-400 hover:underline\">wouterbeets</a></p></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"p-contact h-card bg-white dark:bg-gray-800 p-8 rounded-lg shadow-lg\"><h3 class=\"text-2xl font-bold text-indigo-600 dark:text-pink-400 mb-4\">Contact Information</h3><data class=\"p-name\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var2 string
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(profile.Name)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/contact.templ`, Line: 6, Col: 43}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\"></data><p class=\"p-adr h-adr text-gray-700 dark:text-gray-200 mb-2\"><strong>Address:</strong> <span class=\"p-street-address\">2 avenue de Boran</span>, <span class=\"p-postal-code\">60260</span> <span class=\"p-locality\">Lamorlaye</span><data class=\"p-country-name\" value=\"France\"></data></p><p class=\"text-gray-700 dark:text-gray-200 mb-2\"><strong>Phone:</strong> <a href=\"tel:+33769527759\" class=\"p-tel text-indigo-600 dark:text-pink-400 hover:underline\">+33 7 69 52 77 59</a></p><p class=\"text-gray-700 dark:text-gray-200 mb-2\"><strong>Email:</strong> <a href=\"mailto:beetswouter@gmail.com\" class=\"u-email text-indigo-600 dark:text-pink-400 hover:underline\">beetswouter@gmail.com</a></p>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for _, link := range profile.Links {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<p class=\"text-gray-700 dark:text-gray-200\"><strong>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var3 string
+			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(link.Name)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/contact.templ`, Line: 11, Col: 66}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, ":</strong> <a href=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var4 templ.SafeURL
+			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL(link.URL))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/contact.templ`, Line: 11, Col: 112}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\" target=\"_blank\" rel=\"me\" class=\"u-url text-indigo-600 dark:text-pink-400 hover:underline\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var5 string
+			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(link.URL)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/contact.templ`, Line: 11, Col: 215}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</a></p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -0,0 +1,143 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Month is a calendar month, or a whole year when Month is 0.
+// Content files write it as "2013-09" or "2013".
+type Month struct {
+	Year  int
+	Month time.Month
+}
+
+func ParseMonth(s string) (Month, error) {
+	year, month, hasMonth := strings.Cut(s, "-")
+	y, err := strconv.Atoi(year)
+	if err != nil || len(year) != 4 {
+		return Month{}, fmt.Errorf("invalid month %q, want YYYY or YYYY-MM", s)
+	}
+	if !hasMonth {
+		return Month{Year: y}, nil
+	}
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 {
+		return Month{}, fmt.Errorf("invalid month %q, want YYYY or YYYY-MM", s)
+	}
+	return Month{Year: y, Month: time.Month(m)}, nil
+}
+
+func (m Month) IsZero() bool {
+	return m.Year == 0
+}
+
+func (m Month) String() string {
+	if m.Month == 0 {
+		return strconv.Itoa(m.Year)
+	}
+	return fmt.Sprintf("%04d-%02d", m.Year, int(m.Month))
+}
+
+func (m Month) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON also accepts a bare year number, as YAML and TOML write 2013 unquoted
+func (m *Month) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var year json.Number
+		if json.Unmarshal(data, &year) != nil {
+			return err
+		}
+		s = year.String()
+	}
+	parsed, err := ParseMonth(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Index counts months since year 0; a year without a month counts from January
+func (m Month) Index() int {
+	month := max(int(m.Month), 1)
+	return m.Year*12 + month - 1
+}
+
+func MonthOf(t time.Time) Month {
+	return Month{Year: t.Year(), Month: t.Month()}
+}
+
+var monthAbbreviations = map[string][12]string{
+	"en": {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	"fr": {"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+}
+
+// Format renders the month for lang, e.g. "Sep 2013" or "sept. 2013"
+func (m Month) Format(lang string) string {
+	if m.Month == 0 {
+		return strconv.Itoa(m.Year)
+	}
+	names, ok := monthAbbreviations[lang]
+	if !ok {
+		names = monthAbbreviations["en"]
+	}
+	return names[m.Month-1] + " " + strconv.Itoa(m.Year)
+}
+
+// FormatPeriod renders a date range such as "sept. 2013 – 2016"; a nil end is ongoing
+func FormatPeriod(start Month, end *Month, lang string) string {
+	if end == nil {
+		return start.Format(lang) + " – " + GetTranslation("present", lang)
+	}
+	return start.Format(lang) + " – " + end.Format(lang)
+}
+
+// MonthsBetween counts the months from start to end, or to now when end is nil
+func MonthsBetween(start Month, end *Month, now time.Time) int {
+	last := MonthOf(now)
+	if end != nil {
+		last = *end
+	}
+	return max(last.Index()-start.Index(), 0)
+}
+
+// FormatDuration renders a number of months as "2 yrs 3 mos" or "2 ans 3 mois"
+func FormatDuration(months int, lang string) string {
+	parts := []string{}
+	if years := months / 12; years > 0 {
+		parts = append(parts, Plural(lang, "duration_years", years))
+	}
+	if rest := months % 12; rest > 0 || months == 0 {
+		parts = append(parts, Plural(lang, "duration_months", rest))
+	}
+	return strings.Join(parts, " ")
+}
+
+// PeriodLabel falls back to the legacy Period text when it couldn't be migrated
+func (item ExperienceItem) PeriodLabel(lang string) string {
+	if item.Start.IsZero() {
+		return item.Period
+	}
+	return FormatPeriod(item.Start, item.End, lang)
+}
+
+func (item ExperienceItem) Duration(lang string) string {
+	if item.Start.IsZero() {
+		return ""
+	}
+	return FormatDuration(MonthsBetween(item.Start, item.End, time.Now()), lang)
+}
+
+func (item EducationItem) PeriodLabel(lang string) string {
+	if item.Start.IsZero() {
+		return item.Period
+	}
+	return FormatPeriod(item.Start, item.End, lang)
+}
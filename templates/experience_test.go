@@ -0,0 +1,94 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/a-h/templ"
+	"golang.org/x/net/html"
+)
+
+func renderHTML(t *testing.T, component templ.Component) *html.Node {
+	t.Helper()
+	var b bytes.Buffer
+	if err := component.Render(context.Background(), &b); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := html.Parse(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// findElement returns the first element for which match is true, in document order
+func findElement(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func byID(doc *html.Node, id string) *html.Node {
+	return findElement(doc, func(n *html.Node) bool { v, _ := attr(n, "id"); return v == id })
+}
+
+// checkDisclosure checks that role i's toggle is a button reporting expanded and
+// controlling a region that exists
+func checkDisclosure(t *testing.T, doc *html.Node, i int, expanded bool) {
+	t.Helper()
+	button := byID(doc, fmt.Sprintf("toggle-%d", i))
+	if button == nil || button.Data != "button" {
+		t.Fatalf("role %d has no toggle button", i)
+	}
+	if got, _ := attr(button, "aria-expanded"); got != fmt.Sprint(expanded) {
+		t.Errorf("role %d aria-expanded = %q, want %v", i, got, expanded)
+	}
+	controls, _ := attr(button, "aria-controls")
+	if controls != fmt.Sprintf("role-%d", i) {
+		t.Errorf("role %d aria-controls = %q", i, controls)
+	}
+	if byID(doc, controls) == nil {
+		t.Errorf("role %d controls missing element #%s", i, controls)
+	}
+	form := button.Parent.Parent // button > h3 > form
+	expand := findElement(form, func(n *html.Node) bool { v, _ := attr(n, "name"); return v == "expand" })
+	if expanded == (expand != nil) {
+		t.Errorf("role %d expanded=%v has expand input %v", i, expanded, expand != nil)
+	}
+}
+
+func TestExperienceDisclosure(t *testing.T) {
+	data := testIndexData("en").Experience
+
+	doc := renderHTML(t, ExperienceTemplate(data))
+	checkDisclosure(t, doc, 0, false)
+	checkDisclosure(t, doc, 1, false)
+
+	data.Expand = data.ExperienceItems[1].Slug
+	doc = renderHTML(t, ExperienceTemplate(data))
+	checkDisclosure(t, doc, 0, false)
+	checkDisclosure(t, doc, 1, true)
+}
+
+func TestExperiencePartialsDisclosure(t *testing.T) {
+	item := testIndexData("en").Experience.ExperienceItems[0]
+	checkDisclosure(t, renderHTML(t, ExperienceDetailTemplate(item, "en", "", 3)), 3, true)
+	checkDisclosure(t, renderHTML(t, ExperienceSummaryTemplate(item, "en", "", 3)), 3, false)
+}
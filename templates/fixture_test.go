@@ -0,0 +1,74 @@
+package templates
+
+// testIndexData is a small CV covering every section, shared by the rendering tests
+func testIndexData(lang string) IndexData {
+	end := Month{Year: 2019, Month: 6}
+	skills := []SkillCategory{
+		{Slug: "programming", Name: "Programming Languages", Skills: []SkillItem{
+			{Slug: "go", Name: "Go", Level: "expert", Years: 8},
+		}},
+		{Slug: SpokenCategory, Name: "Spoken Languages", Skills: []SkillItem{
+			{Slug: "dutch", Name: "Dutch", Level: "native"},
+		}},
+	}
+	return IndexData{
+		Profile: ProfileData{
+			Name:     "Ada Lovelace",
+			JobTitle: "Software Architect",
+			Headline: "Building analytical engines",
+			Text:     "Architect of **reliable** systems.",
+			Links:    []ProfileLink{{Name: "GitHub", URL: "https://github.com/ada"}},
+			SpokenLanguages: []SpokenLanguage{
+				{Name: "Dutch", Code: "nl", Level: "native"},
+			},
+		},
+		Experience: ExperienceData{
+			ExperienceItems: []ExperienceItem{
+				{
+					Slug:        "cto-engines",
+					Title:       "CTO",
+					Company:     "Engines Ltd",
+					Start:       Month{Year: 2020, Month: 1},
+					Location:    "London",
+					Summary:     "Led the *engineering* team.",
+					Description: []Bullet{{Title: "Hiring", Text: "Grew the team to 20 people."}},
+					Skills:      []string{"go"},
+				},
+				{
+					Slug:        "developer-mills",
+					Title:       "Developer",
+					Company:     "Mills & Co",
+					Start:       Month{Year: 2015, Month: 9},
+					End:         &end,
+					Summary:     "Wrote Go services.",
+					Description: []Bullet{{Text: "Shipped the billing service."}},
+				},
+			},
+			Language:     lang,
+			Translations: Translations,
+		},
+		Education: EducationData{
+			EducationItems: []EducationItem{
+				{Title: "Mathematics", Institution: "University of London", Start: Month{Year: 2010}, End: &Month{Year: 2014}},
+			},
+			Language:     lang,
+			Translations: Translations,
+		},
+		Projects: ProjectsData{
+			ProjectItems: []ProjectItem{
+				{Slug: "engine", Title: "Engine", Description: "A difference engine in Go.", GitHubLink: "https://github.com/ada/engine", Skills: []string{"go"}},
+			},
+			Language:     lang,
+			Translations: Translations,
+		},
+		Skills: SkillsData{
+			Categories:   skills,
+			Available:    skills,
+			Language:     lang,
+			Translations: Translations,
+		},
+		BaseURL:      "https://ada.example",
+		Translations: Translations,
+		Language:     lang,
+	}
+}
@@ -0,0 +1,105 @@
+package templates
+
+// schema.org structured data describing the CV, embedded as JSON-LD in IndexTemplate
+func ProfileJSONLD(data IndexData) map[string]any {
+	pageURL := data.BaseURL + "/?lang=" + data.Language
+	personID := data.BaseURL + "/#person"
+
+	sameAs := []string{}
+	for _, link := range data.Profile.Links {
+		sameAs = append(sameAs, link.URL)
+	}
+
+	knowsLanguage := []map[string]any{}
+	for _, language := range data.Profile.SpokenLanguages {
+		knowsLanguage = append(knowsLanguage, map[string]any{
+			"@type":         "Language",
+			"name":          language.Name,
+			"alternateName": language.Code,
+		})
+	}
+
+	occupations := []map[string]any{}
+	roles := []map[string]any{}
+	for _, item := range data.Experience.ExperienceItems {
+		occupations = append(occupations, map[string]any{
+			"@type":       "Occupation",
+			"name":        item.Title,
+			"description": PlainText(item.Summary),
+		})
+		roles = append(roles, roleDates(map[string]any{
+			"@type":    "EmployeeRole",
+			"roleName": item.Title,
+			"worksFor": map[string]any{
+				"@type": "Organization",
+				"name":  item.Company,
+			},
+		}, item.Start, item.End))
+	}
+
+	alumniOf := []map[string]any{}
+	for _, item := range data.Education.EducationItems {
+		alumniOf = append(alumniOf, roleDates(map[string]any{
+			"@type":    "OrganizationRole",
+			"roleName": item.Title,
+			"alumniOf": map[string]any{
+				"@type": "EducationalOrganization",
+				"name":  item.Institution,
+			},
+		}, item.Start, item.End))
+	}
+
+	// Spoken languages are already described by knowsLanguage
+	knowsAbout := []string{}
+	for _, category := range data.Skills.Available {
+		if category.Slug != SpokenCategory {
+			knowsAbout = append(knowsAbout, SkillsData{Categories: []SkillCategory{category}}.Names()...)
+		}
+	}
+
+	graph := []map[string]any{
+		{
+			"@type":         "Person",
+			"@id":           personID,
+			"name":          data.Profile.Name,
+			"jobTitle":      data.Profile.JobTitle,
+			"description":   PlainText(data.Profile.Text),
+			"url":           pageURL,
+			"image":         data.BaseURL + OGImage(data.Language, "home"),
+			"sameAs":        sameAs,
+			"knowsLanguage": knowsLanguage,
+			"knowsAbout":    knowsAbout,
+			"hasOccupation": occupations,
+			"worksFor":      roles,
+			"alumniOf":      alumniOf,
+		},
+	}
+	for _, item := range data.Projects.ProjectItems {
+		graph = append(graph, map[string]any{
+			"@type":          "SoftwareSourceCode",
+			"name":           item.Title,
+			"description":    PlainText(item.Description),
+			"codeRepository": item.GitHubLink,
+			"inLanguage":     data.Language,
+			"author":         map[string]any{"@id": personID},
+		})
+	}
+
+	return map[string]any{
+		"@context": "https://schema.org",
+		"@graph":   graph,
+	}
+}
+
+// roleDates adds the ISO 8601 startDate and endDate of a role; an ongoing role has no
+// endDate and a role whose dates couldn't be parsed has neither
+func roleDates(role map[string]any, start Month, end *Month) map[string]any {
+	if start.IsZero() {
+		return role
+	}
+	role["startDate"] = start.String()
+	if end != nil {
+		role["endDate"] = end.String()
+	}
+	return role
+}
@@ -0,0 +1,134 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// schema.org properties the CV may use, by type
+var schemaProperties = map[string][]string{
+	"Person":                  {"@id", "name", "jobTitle", "description", "url", "image", "sameAs", "knowsLanguage", "knowsAbout", "hasOccupation", "worksFor", "alumniOf"},
+	"Language":                {"name", "alternateName"},
+	"Occupation":              {"name", "description"},
+	"EmployeeRole":            {"roleName", "worksFor", "startDate", "endDate"},
+	"OrganizationRole":        {"roleName", "alumniOf", "startDate", "endDate"},
+	"Organization":            {"name"},
+	"EducationalOrganization": {"name"},
+	"SoftwareSourceCode":      {"name", "description", "codeRepository", "inLanguage", "author"},
+}
+
+var isoDate = regexp.MustCompile(`^\d{4}(-\d{2})?$`)
+
+// validateSchema checks that every object in v has a known @type and only that type's
+// properties, and that dates are ISO 8601
+func validateSchema(t *testing.T, path string, v any) {
+	t.Helper()
+	switch v := v.(type) {
+	case []any:
+		for i, item := range v {
+			validateSchema(t, fmt.Sprintf("%s[%d]", path, i), item)
+		}
+	case map[string]any:
+		if _, ok := v["@id"]; ok && len(v) == 1 {
+			return // A reference to a node in the graph
+		}
+		typ, _ := v["@type"].(string)
+		props, ok := schemaProperties[typ]
+		if !ok {
+			t.Errorf("%s: unexpected @type %q", path, v["@type"])
+			return
+		}
+		for key, value := range v {
+			if key == "@type" {
+				continue
+			}
+			if !slices.Contains(props, key) {
+				t.Errorf("%s: %s has no property %q", path, typ, key)
+			}
+			if key == "startDate" || key == "endDate" {
+				if s, _ := value.(string); !isoDate.MatchString(s) {
+					t.Errorf("%s: %s %v is not an ISO 8601 date", path, key, value)
+				}
+			}
+			validateSchema(t, path+"."+key, value)
+		}
+	}
+}
+
+func renderJSONLD(t *testing.T, data IndexData) map[string]any {
+	t.Helper()
+	var page bytes.Buffer
+	if err := IndexTemplate(data).Render(context.Background(), &page); err != nil {
+		t.Fatal(err)
+	}
+	script := regexp.MustCompile(`(?s)<script id="profile-jsonld" type="application/ld\+json">(.*?)</script>`).FindSubmatch(page.Bytes())
+	if script == nil {
+		t.Fatal("no JSON-LD script in the page")
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(script[1], &doc); err != nil {
+		t.Fatalf("JSON-LD doesn't parse: %v", err)
+	}
+	return doc
+}
+
+func TestProfileJSONLD(t *testing.T) {
+	doc := renderJSONLD(t, testIndexData("en"))
+	if doc["@context"] != "https://schema.org" {
+		t.Errorf("@context = %v", doc["@context"])
+	}
+	graph, _ := doc["@graph"].([]any)
+	if len(graph) != 2 {
+		t.Fatalf("graph has %d nodes, want the person and one project", len(graph))
+	}
+	validateSchema(t, "@graph", graph)
+
+	person := graph[0].(map[string]any)
+	if person["description"] != "Architect of reliable systems." {
+		t.Errorf("description = %q, want the plain text", person["description"])
+	}
+	if got := person["knowsAbout"]; !slices.Equal(toStrings(got), []string{"Go"}) {
+		t.Errorf("knowsAbout = %v, want the skills without spoken languages", got)
+	}
+
+	roles := person["worksFor"].([]any)
+	current, past := roles[0].(map[string]any), roles[1].(map[string]any)
+	if current["startDate"] != "2020-01" {
+		t.Errorf("current role startDate = %v", current["startDate"])
+	}
+	if _, ok := current["endDate"]; ok {
+		t.Errorf("ongoing role has endDate %v", current["endDate"])
+	}
+	if past["startDate"] != "2015-09" || past["endDate"] != "2019-06" {
+		t.Errorf("past role dates = %v – %v", past["startDate"], past["endDate"])
+	}
+	school := person["alumniOf"].([]any)[0].(map[string]any)
+	if school["startDate"] != "2010" || school["endDate"] != "2014" {
+		t.Errorf("education dates = %v – %v", school["startDate"], school["endDate"])
+	}
+}
+
+func TestProfileJSONLDUnparsedDates(t *testing.T) {
+	data := testIndexData("en")
+	data.Experience.ExperienceItems[0].Start = Month{}
+	data.Experience.ExperienceItems[0].Period = "Some time ago"
+	role := renderJSONLD(t, data)["@graph"].([]any)[0].(map[string]any)["worksFor"].([]any)[0].(map[string]any)
+	if _, ok := role["startDate"]; ok {
+		t.Errorf("role without parsed dates has startDate %v", role["startDate"])
+	}
+}
+
+func toStrings(v any) []string {
+	items, _ := v.([]any)
+	s := []string{}
+	for _, item := range items {
+		str, _ := item.(string)
+		s = append(s, str)
+	}
+	return s
+}
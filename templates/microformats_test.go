@@ -0,0 +1,104 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"slices"
+	"testing"
+
+	"willnorris.com/go/microformats"
+)
+
+func parseMicroformats(t *testing.T, data IndexData) []*microformats.Microformat {
+	t.Helper()
+	var page bytes.Buffer
+	if err := IndexTemplate(data).Render(context.Background(), &page); err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse(data.BaseURL + "/")
+	return microformats.Parse(&page, base).Items
+}
+
+// property returns the values of a property that are plain strings
+func property(item *microformats.Microformat, name string) []string {
+	values := []string{}
+	for _, v := range item.Properties[name] {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// embedded returns the microformats nested as values of a property
+func embedded(item *microformats.Microformat, name string) []*microformats.Microformat {
+	items := []*microformats.Microformat{}
+	for _, v := range item.Properties[name] {
+		if mf, ok := v.(*microformats.Microformat); ok {
+			items = append(items, mf)
+		}
+	}
+	return items
+}
+
+func TestIndexMicroformats(t *testing.T) {
+	items := parseMicroformats(t, testIndexData("en"))
+	if len(items) != 1 || !slices.Equal(items[0].Type, []string{"h-resume"}) {
+		t.Fatalf("top-level items = %v, want a single h-resume", items)
+	}
+	resume := items[0]
+
+	contacts := embedded(resume, "contact")
+	if len(contacts) == 0 || !slices.Equal(contacts[0].Type, []string{"h-card"}) {
+		t.Fatalf("contact = %v, want an h-card", resume.Properties["contact"])
+	}
+	card := contacts[0]
+	for name, want := range map[string]string{
+		"name":      "Ada Lovelace",
+		"job-title": "Software Architect",
+		"note":      "Architect of reliable systems.",
+		"uid":       "https://ada.example/",
+	} {
+		if got := property(card, name); !slices.Equal(got, []string{want}) {
+			t.Errorf("h-card %s = %q, want %q", name, got, want)
+		}
+	}
+	if got := property(card, "url"); !slices.Contains(got, "https://github.com/ada") {
+		t.Errorf("h-card url = %q, want the profile links", got)
+	}
+
+	roles := embedded(resume, "experience")
+	if len(roles) != 2 {
+		t.Fatalf("got %d experience h-events, want 2", len(roles))
+	}
+	for i, want := range []struct{ name, org, start, end string }{
+		{"CTO", "Engines Ltd", "2020-01", ""},
+		{"Developer", "Mills & Co", "2015-09", "2019-06"},
+	} {
+		role := roles[i]
+		if !slices.Equal(role.Type, []string{"h-event"}) {
+			t.Errorf("experience %d type = %v", i, role.Type)
+		}
+		if got := property(role, "name"); !slices.Equal(got, []string{want.name}) {
+			t.Errorf("experience %d name = %q, want %q", i, got, want.name)
+		}
+		if got := property(role, "start"); !slices.Equal(got, []string{want.start}) {
+			t.Errorf("experience %d start = %q, want %q", i, got, want.start)
+		}
+		if got := property(role, "end"); want.end != "" && !slices.Equal(got, []string{want.end}) || want.end == "" && len(got) > 0 {
+			t.Errorf("experience %d end = %q, want %q", i, got, want.end)
+		}
+		if orgs := embedded(role, "location"); len(orgs) != 1 || !slices.Equal(property(orgs[0], "org"), []string{want.org}) {
+			t.Errorf("experience %d location = %v, want an h-card for %s", i, role.Properties["location"], want.org)
+		}
+	}
+
+	schools := embedded(resume, "education")
+	if len(schools) != 1 || !slices.Equal(property(schools[0], "name"), []string{"Mathematics"}) || !slices.Equal(property(schools[0], "start"), []string{"2010"}) {
+		t.Errorf("education = %v", resume.Properties["education"])
+	}
+	if got := property(resume, "skill"); !slices.Contains(got, "Go") {
+		t.Errorf("skill = %q, want Go", got)
+	}
+}
@@ -0,0 +1,41 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGitHubRepo(t *testing.T) {
+	for link, want := range map[string]string{
+		"https://github.com/ada/note-g":             "ada/note-g",
+		"https://github.com/ada/note-g/tree/master": "ada/note-g",
+		"https://github.com/ada":                    "",
+		"https://github.com/ada/":                   "",
+		"https://gitlab.com/ada/note-g":             "",
+		"http://github.com/ada/note-g":              "",
+		"":                                          "",
+	} {
+		if got, ok := GitHubRepo(link); got != want || ok != (want != "") {
+			t.Errorf("GitHubRepo(%q) = %q, %v, want %q", link, got, ok, want)
+		}
+	}
+}
+
+func TestProjectsWithoutGitHubLink(t *testing.T) {
+	var out bytes.Buffer
+	err := ProjectsTemplate(ProjectsData{ProjectItems: []ProjectItem{
+		{Slug: "note-g", Title: "Note G", GitHubLink: "https://github.com/ada/note-g/tree/master"},
+		{Slug: "loom-cards", Title: "Loom Cards"},
+	}, Language: "en"}).Render(context.Background(), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(out.String(), `hx-get="/api/github-stats/`); got != 1 {
+		t.Errorf("%d stats fetches, want 1 for the linked project", got)
+	}
+	if !strings.Contains(out.String(), `hx-get="/api/github-stats/ada/note-g"`) {
+		t.Error("stats are not fetched for ada/note-g")
+	}
+}
@@ -0,0 +1,189 @@
+package templates
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Content text supports a Markdown subset: **bold**, *italics* or _italics_, `code`
+// and [links](https://example.com). It is parsed by goldmark with only the paragraph
+// and those inline parsers, so raw HTML, autolinks, headings and lists stay plain text.
+
+// inlineMarkdown parses content text into paragraphs of inline nodes
+var inlineMarkdown = parser.NewParser(
+	parser.WithBlockParsers(util.Prioritized(parser.NewParagraphParser(), 100)),
+	parser.WithInlineParsers(
+		util.Prioritized(parser.NewCodeSpanParser(), 100),
+		util.Prioritized(parser.NewLinkParser(), 200),
+		util.Prioritized(parser.NewEmphasisParser(), 500),
+	),
+)
+
+type inlineKind int
+
+const (
+	inlineText inlineKind = iota
+	inlineStrong
+	inlineEmphasis
+	inlineCode
+	inlineLink
+)
+
+type inlineNode struct {
+	Kind     inlineKind
+	Text     string // Text of text and code nodes
+	URL      string
+	Children []inlineNode
+}
+
+// parseInline parses s into the inline nodes RichText renders; paragraphs are joined
+// by a space
+func parseInline(s string) []inlineNode {
+	source := []byte(s)
+	doc := inlineMarkdown.Parse(text.NewReader(source))
+	nodes := []inlineNode{}
+	for block := doc.FirstChild(); block != nil; block = block.NextSibling() {
+		if block != doc.FirstChild() {
+			nodes = appendText(nodes, " ")
+		}
+		nodes = append(nodes, inlineChildren(block, source)...)
+	}
+	return nodes
+}
+
+// inlineChildren converts the inline children of n, keeping only the supported kinds:
+// anything else, such as images, is reduced to its text
+func inlineChildren(n ast.Node, source []byte) []inlineNode {
+	nodes := []inlineNode{}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			nodes = appendText(nodes, unescape(c.Value(source)))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				nodes = appendText(nodes, " ")
+			}
+		case *ast.String:
+			nodes = appendText(nodes, string(c.Value))
+		case *ast.CodeSpan:
+			var code strings.Builder
+			for t := c.FirstChild(); t != nil; t = t.NextSibling() {
+				if t, ok := t.(*ast.Text); ok {
+					code.WriteString(strings.ReplaceAll(string(t.Value(source)), "\n", " "))
+				}
+			}
+			nodes = append(nodes, inlineNode{Kind: inlineCode, Text: code.String()})
+		case *ast.Emphasis:
+			kind := inlineEmphasis
+			if c.Level == 2 {
+				kind = inlineStrong
+			}
+			nodes = append(nodes, inlineNode{Kind: kind, Children: inlineChildren(c, source)})
+		case *ast.Link:
+			children := inlineChildren(c, source)
+			if url := unescape(c.Destination); safeURL(url) {
+				nodes = append(nodes, inlineNode{Kind: inlineLink, URL: url, Children: children})
+			} else {
+				nodes = append(nodes, children...)
+			}
+		default:
+			nodes = append(nodes, inlineChildren(c, source)...)
+		}
+	}
+	return nodes
+}
+
+// appendText adds s to nodes, merging it into a trailing text node
+func appendText(nodes []inlineNode, s string) []inlineNode {
+	if last := len(nodes) - 1; last >= 0 && nodes[last].Kind == inlineText {
+		nodes[last].Text += s
+		return nodes
+	}
+	return append(nodes, inlineNode{Kind: inlineText, Text: s})
+}
+
+// unescape resolves backslash escapes and character references as goldmark's HTML
+// renderer does
+func unescape(b []byte) string {
+	return string(util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(b))))
+}
+
+// safeURL accepts web, mail and site-relative URLs. Backslashes, whitespace and control
+// characters are refused: browsers read "/\evil.com" as "//evil.com" and drop tabs.
+func safeURL(url string) bool {
+	if strings.ContainsFunc(url, func(r rune) bool { return r == '\\' || r <= ' ' || r == 0x7f }) {
+		return false
+	}
+	lower := strings.ToLower(url)
+	for _, prefix := range []string{"https://", "http://", "mailto:"} {
+		if strings.HasPrefix(lower, prefix) {
+			return len(url) > len(prefix)
+		}
+	}
+	return strings.HasPrefix(url, "/") && !strings.HasPrefix(url, "//") || strings.HasPrefix(url, "#")
+}
+
+// PlainText strips the inline Markdown from s, for text, JSON-LD and meta tags
+func PlainText(s string) string {
+	var b strings.Builder
+	writePlain(&b, parseInline(s))
+	return b.String()
+}
+
+func writePlain(b *strings.Builder, nodes []inlineNode) {
+	for _, node := range nodes {
+		if node.Kind == inlineText || node.Kind == inlineCode {
+			b.WriteString(node.Text)
+		} else {
+			writePlain(b, node.Children)
+		}
+	}
+}
+
+// Bullet is a description bullet with an optional lead title
+type Bullet struct {
+	Title string `json:"Title,omitempty"`
+	Text  string `json:"Text"`
+}
+
+// UnmarshalJSON also accepts the legacy plain string form, splitting a short lead
+// such as "Payment Systems: Led the integration..." or "**Payment Systems**: ..."
+// into the title
+func (b *Bullet) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		type bullet Bullet
+		return json.Unmarshal(data, (*bullet)(b))
+	}
+	*b = splitBullet(s)
+	return nil
+}
+
+// splitBullet splits a legacy "Lead: body" bullet. A lead is at most 100 characters
+// and not a sentence, so text that merely contains a colon stays whole.
+func splitBullet(s string) Bullet {
+	lead, body, found := strings.Cut(s, ":")
+	lead = strings.TrimSpace(lead)
+	if bold, ok := strings.CutPrefix(lead, "**"); ok {
+		if bold, ok = strings.CutSuffix(bold, "**"); ok && !strings.Contains(bold, "**") {
+			lead = strings.TrimSpace(bold)
+		}
+	}
+	if !found || !strings.HasPrefix(body, " ") || lead == "" || utf8.RuneCountInString(lead) > 100 || strings.Contains(lead, ". ") {
+		return Bullet{Text: s}
+	}
+	return Bullet{Title: lead, Text: strings.TrimSpace(body)}
+}
+
+// String renders the bullet as plain text, "Title: text"
+func (b Bullet) String() string {
+	if b.Title == "" {
+		return PlainText(b.Text)
+	}
+	return PlainText(b.Title) + ": " + PlainText(b.Text)
+}
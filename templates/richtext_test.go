@@ -0,0 +1,120 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func renderRichText(t *testing.T, s string) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := RichText(s).Render(context.Background(), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestRichText(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       []string
+		notWant    []string
+	}{
+		{name: "strong", text: "**bold**", want: []string{"<strong", ">bold</strong>"}},
+		{name: "emphasis", text: "*one* and _two_", want: []string{">one</em>", ">two</em>"}},
+		{name: "code", text: "`a*b*`", want: []string{">a*b*</code>"}},
+		{name: "snake case", text: "snake_case_name", want: []string{"snake_case_name"}, notWant: []string{"<em"}},
+		{name: "https link", text: "[site](https://ada.example/x)", want: []string{`href="https://ada.example/x"`, ">site</a>"}},
+		{name: "relative link", text: "[cv](/cv)", want: []string{`href="/cv"`}},
+		{name: "mailto link", text: "[mail](mailto:ada@ada.example)", want: []string{`href="mailto:ada@ada.example"`}},
+		{name: "javascript link", text: "[x](javascript:alert(1))", want: []string{"x"}, notWant: []string{"<a", "javascript"}},
+		{name: "backslash link", text: `[x](/\evil.com)`, notWant: []string{"<a", "evil.com"}},
+		{name: "protocol-relative link", text: "[x](//evil.com)", notWant: []string{"<a", "evil.com"}},
+		{name: "raw html", text: "<b onclick=x>hi</b>", want: []string{"&lt;b onclick=x&gt;hi&lt;/b&gt;"}, notWant: []string{"<b"}},
+		{name: "autolink", text: "<https://evil.com>", notWant: []string{"<a"}},
+		{name: "escaped", text: `\*literal\*`, want: []string{"*literal*"}, notWant: []string{"<em"}},
+		{name: "block syntax", text: "# heading\n- item", want: []string{"# heading - item"}, notWant: []string{"<h1", "<li"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderRichText(t, tt.text)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("RichText(%q) = %q, want %q", tt.text, got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("RichText(%q) = %q, contains %q", tt.text, got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	for url, want := range map[string]bool{
+		"https://ada.example":    true,
+		"HTTP://ada.example":     true,
+		"mailto:ada@ada.example": true,
+		"/cv":                    true,
+		"#contact":               true,
+		"https://":               false,
+		"//evil.com":             false,
+		`/\evil.com`:             false,
+		`https://ada.example\x`:  false,
+		"/\t/evil.com":           false,
+		"/ /evil.com":            false,
+		"javascript:alert(1)":    false,
+		"data:text/html,x":       false,
+		"cv":                     false,
+	} {
+		if got := safeURL(url); got != want {
+			t.Errorf("safeURL(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText("Led **Go** _services_ with `gRPC` at [Engines](https://ada.example)\n\nand more")
+	if want := "Led Go services with gRPC at Engines and more"; got != want {
+		t.Errorf("PlainText = %q, want %q", got, want)
+	}
+}
+
+func TestBulletUnmarshal(t *testing.T) {
+	var bullets []Bullet
+	err := json.Unmarshal([]byte(`[
+		"Payment Systems: Led the integration of Stripe.",
+		"**Event Sourcing**: Created a framework in Go.",
+		"Shipped 3 apps. Ratio: 2:1 in favour of iOS",
+		"No lead at all",
+		"Time:10am",
+		{"Title": "Scale", "Text": "**10x** traffic"}
+	]`), &bullets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Bullet{
+		{Title: "Payment Systems", Text: "Led the integration of Stripe."},
+		{Title: "Event Sourcing", Text: "Created a framework in Go."},
+		{Text: "Shipped 3 apps. Ratio: 2:1 in favour of iOS"},
+		{Text: "No lead at all"},
+		{Text: "Time:10am"},
+		{Title: "Scale", Text: "**10x** traffic"},
+	}
+	if len(bullets) != len(want) {
+		t.Fatalf("bullets = %+v, want %+v", bullets, want)
+	}
+	for i := range want {
+		if bullets[i] != want[i] {
+			t.Errorf("bullet %d = %+v, want %+v", i, bullets[i], want[i])
+		}
+	}
+	if got := bullets[5].String(); got != "Scale: 10x traffic" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -1,5 +1,8 @@
 package templates
 
+// Supported languages, in the order they are offered
+var Languages = []string{"en", "fr"}
+
 // Translation maps for static text
 var Translations = map[string]map[string]string{
 	"name_label": {
@@ -18,18 +21,6 @@ var Translations = map[string]map[string]string{
 		"en": "Send Message",
 		"fr": "Envoyer le Message",
 	},
-	"loading_experience": {
-		"en": "Loading experience...",
-		"fr": "Chargement de l'expérience...",
-	},
-	"loading_education": {
-		"en": "Loading education...",
-		"fr": "Chargement de l'éducation...",
-	},
-	"loading_projects": {
-		"en": "Loading projects...",
-		"fr": "Chargement des projets...",
-	},
 	"loading_contact": {
 		"en": "Loading contact form...",
 		"fr": "Chargement du formulaire de contact...",
@@ -38,6 +29,122 @@ var Translations = map[string]map[string]string{
 		"en": "Filter skills...",
 		"fr": "Filtrer les compétences...",
 	},
+	"all_skills": {
+		"en": "All",
+		"fr": "Toutes",
+	},
+	"level_expert": {
+		"en": "Expert",
+		"fr": "Expert",
+	},
+	"level_advanced": {
+		"en": "Advanced",
+		"fr": "Avancé",
+	},
+	"level_intermediate": {
+		"en": "Intermediate",
+		"fr": "Intermédiaire",
+	},
+	"level_native": {
+		"en": "Native",
+		"fr": "Langue maternelle",
+	},
+	"level_fluent": {
+		"en": "Fluent",
+		"fr": "Courant",
+	},
+	"years_of_experience": {
+		"en": "%d years",
+		"fr": "%d ans",
+	},
+	"years_of_experience_one": {
+		"en": "%d year",
+		"fr": "%d an",
+	},
+	"project_count": {
+		"en": "%d projects",
+		"fr": "%d projets",
+	},
+	"project_count_one": {
+		"en": "%d project",
+		"fr": "%d projet",
+	},
+	"expand_role": {
+		"en": "Show details",
+		"fr": "Voir les détails",
+	},
+	"collapse_role": {
+		"en": "Hide details",
+		"fr": "Masquer les détails",
+	},
+	"read_case_study": {
+		"en": "Read the case study",
+		"fr": "Lire l'étude de cas",
+	},
+	"back_to_cv": {
+		"en": "Back to the CV",
+		"fr": "Retour au CV",
+	},
+	"role_count": {
+		"en": "%d roles",
+		"fr": "%d postes",
+	},
+	"role_count_one": {
+		"en": "%d role",
+		"fr": "%d poste",
+	},
+	"roles_using_skill": {
+		"en": "Roles using %s",
+		"fr": "Postes utilisant %s",
+	},
+	"clear_filter": {
+		"en": "Show all roles",
+		"fr": "Afficher tous les postes",
+	},
+	"no_roles_with_skill": {
+		"en": "No roles are tagged with this skill yet.",
+		"fr": "Aucun poste n'est encore associé à cette compétence.",
+	},
+	"show_roles_with_skill": {
+		"en": "Filter the timeline by this skill",
+		"fr": "Filtrer le parcours par cette compétence",
+	},
+	"used_at": {
+		"en": "Used at",
+		"fr": "Utilisé chez",
+	},
+	"present": {
+		"en": "present",
+		"fr": "aujourd'hui",
+	},
+	"duration_years": {
+		"en": "%d yrs",
+		"fr": "%d ans",
+	},
+	"duration_years_one": {
+		"en": "%d yr",
+		"fr": "%d an",
+	},
+	"duration_months": {
+		"en": "%d mos",
+		"fr": "%d mois",
+	},
+	"duration_months_one": {
+		"en": "%d mo",
+		"fr": "%d mois",
+	},
+	"chart_skills_title": {
+		"en": "Average proficiency by skill category",
+		"fr": "Niveau moyen par catégorie de compétences",
+	},
+	"chart_timeline_title": {
+		"en": "Career timeline",
+		"fr": "Chronologie du parcours",
+	},
+	"no_matching_skills": {
+		"en": "No matching skills",
+		"fr": "Aucune compétence correspondante",
+	},
 	"professional_experience": {
 		"en": "Professional Experience",
 		"fr": "Expérience Professionnelle",
@@ -90,6 +197,30 @@ var Translations = map[string]map[string]string{
 		"en": "View on GitHub",
 		"fr": "Voir sur GitHub",
 	},
+	"mentioned_by": {
+		"en": "Mentioned by",
+		"fr": "Mentionné par",
+	},
+	"terminal_hint": {
+		"en": "Add ?expand=all for full role descriptions, ?lang=fr for French and ?width=120 to fit your terminal.",
+		"fr": "Ajoutez ?expand=all pour le détail des postes, ?lang=en pour l'anglais et ?width=120 pour adapter la largeur.",
+	},
+	"ssh_help": {
+		"en": "↑↓ select · enter expand · ←→ or 1-4 sections · l language · q quit",
+		"fr": "↑↓ choisir · entrée détailler · ←→ ou 1-4 sections · l langue · q quitter",
+	},
+	"offline_title": {
+		"en": "You're offline",
+		"fr": "Vous êtes hors ligne",
+	},
+	"offline_message": {
+		"en": "This page isn't available offline yet. Check your connection and try again.",
+		"fr": "Cette page n'est pas encore disponible hors ligne. Vérifiez votre connexion et réessayez.",
+	},
+	"retry": {
+		"en": "Try again",
+		"fr": "Réessayer",
+	},
 }
 
 // Helper to get translation
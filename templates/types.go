@@ -1,24 +1,50 @@
 package templates
 
+import "github.com/a-h/templ"
+
 // Define structs for data
 type ExperienceItem struct {
+	Slug        string   `json:"Slug"`
 	Title       string   `json:"Title"`
 	Company     string   `json:"Company"`
-	Period      string   `json:"Period"`
-	Description []string `json:"Description"`
+	Start       Month    `json:"Start"`
+	End         *Month   `json:"End,omitempty"` // Nil while the role is ongoing
+	Location    string   `json:"Location,omitempty"`
+	Period      string   `json:"Period,omitempty"` // Legacy free-text dates, migrated on load
+	Description []Bullet `json:"Description"`
 	Summary     string   `json:"Summary"`
+	Skills      []string `json:"Skills,omitempty"` // Skill slugs
+	Highlighted []bool   `json:"-"`                // Description bullets matching the skill filter
+	CaseStudy   bool     `json:"-"`                // Whether /experience/{Slug} has a case study
 }
 
 type ExperienceData struct {
 	ExperienceItems []ExperienceItem `json:"ExperienceItems"`
+	Expand          string           `json:"-"` // Slug of the item rendered expanded
+	Skill           string           `json:"-"` // Slug of the skill roles are filtered by
+	SkillName       string           `json:"-"`
 	Language        string           `json:"-"`
 	Translations    map[string]map[string]string `json:"-"`
 }
 
+// CaseStudyData is a role's long-form case study page; Body is rendered Markdown
+type CaseStudyData struct {
+	Item      ExperienceItem
+	Body      templ.Component
+	Profile   ProfileData
+	BaseURL   string
+	Language  string
+	Languages []string // Languages the case study is written in
+}
+
 type EducationItem struct {
-	Title       string `json:"Title"`
-	Institution string `json:"Institution"`
-	Period      string `json:"Period"`
+	Slug          string `json:"Slug,omitempty"` // Matches the Markdown item that replaces it
+	Title         string `json:"Title"`
+	Institution   string `json:"Institution"`
+	Start         Month  `json:"Start"`
+	End           *Month `json:"End,omitempty"`
+	Qualification string `json:"Qualification,omitempty"`
+	Period        string `json:"Period,omitempty"` // Legacy free-text dates, migrated on load
 }
 
 type EducationData struct {
@@ -28,27 +54,103 @@ type EducationData struct {
 }
 
 type ProjectItem struct {
-	Title       string `json:"Title"`
-	Description string `json:"Description"`
-	GitHubLink  string `json:"GitHubLink"`
+	Slug        string   `json:"Slug"`
+	Title       string   `json:"Title"`
+	Description string   `json:"Description"`
+	GitHubLink  string   `json:"GitHubLink"`
+	Skills      []string `json:"Skills,omitempty"` // Skill slugs
 }
 
 type ProjectsData struct {
 	ProjectItems []ProjectItem `json:"ProjectItems"`
+	Mentions     map[string][]Mention `json:"-"` // Approved webmentions by project slug
 	Language     string        `json:"-"`
 	Translations map[string]map[string]string `json:"-"`
 }
 
+// SkillItem is one skill; Level is a proficiency key translated as level_<Level>
+type SkillItem struct {
+	Slug  string `json:"Slug"`
+	Name  string `json:"Name"`
+	Level string `json:"Level"`
+	Years int    `json:"Years,omitempty"` // Computed from tagged roles when not set
+
+	UsedAt   []SkillUse `json:"-"` // Roles tagged with the skill
+	Projects []SkillUse `json:"-"` // Projects tagged with the skill
+}
+
+// SkillUse links a skill to a role or project where it was used
+type SkillUse struct {
+	Title  string
+	Detail string
+	URL    string
+}
+
+type SkillCategory struct {
+	Slug   string      `json:"Slug"`
+	Name   string      `json:"Name"`
+	Skills []SkillItem `json:"Skills"`
+}
+
+// SpokenCategory is the slug of the skill category listing spoken languages
+const SpokenCategory = "spoken"
+
+type SkillsData struct {
+	Categories   []SkillCategory `json:"Categories"`
+	Available    []SkillCategory `json:"-"` // Every category, for the filter
+	Category     string          `json:"-"` // Slug of the selected category, empty for all
+	Query        string          `json:"-"`
+	Language     string          `json:"-"`
+	Translations map[string]map[string]string `json:"-"`
+}
+
+// Names lists every skill label, in category order
+func (d SkillsData) Names() []string {
+	names := []string{}
+	for _, category := range d.Categories {
+		for _, skill := range category.Skills {
+			names = append(names, skill.Name)
+		}
+	}
+	return names
+}
+
+// Mention is an approved webmention shown on a project card
+type Mention struct {
+	Source string
+	Title  string
+}
+
+type ProfileLink struct {
+	Name string `json:"Name"`
+	URL  string `json:"URL"`
+}
+
+type SpokenLanguage struct {
+	Name  string `json:"Name"`
+	Code  string `json:"Code"` // BCP 47 language tag
+	Level string `json:"Level"`
+}
+
 type ProfileData struct {
+	Name     string `json:"Name"`
+	JobTitle string `json:"JobTitle"`
+	Headline string `json:"Headline"`
 	Title    string `json:"Title"`
 	Text     string `json:"Text"`
+	Links           []ProfileLink    `json:"Links"`
+	SpokenLanguages []SpokenLanguage `json:"SpokenLanguages"`
 	Language string `json:"-"`
 	Translations map[string]map[string]string `json:"-"`
 }
 
 type IndexData struct {
-	Skills       []string                      `json:"Skills"`
+	Skills       SkillsData                    `json:"-"`
 	Profile      ProfileData                   `json:"Profile"`
+	Experience   ExperienceData                `json:"-"`
+	Education    EducationData                 `json:"-"`
+	Projects     ProjectsData                  `json:"-"`
+	BaseURL      string                        `json:"-"` // Public site URL without trailing slash
 	Language     string                        `json:"-"`
 	Translations map[string]map[string]string `json:"-"`
 }
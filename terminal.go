@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"testserver/templates"
+)
+
+// Plain-text rendering of the CV for terminal clients such as curl
+
+const (
+	defaultTextWidth = 80
+	minTextWidth     = 40
+	maxTextWidth     = 200
+)
+
+var terminalAgents = []string{"curl/", "wget/", "httpie/"}
+
+// wantsText reports whether the client is a terminal tool or prefers text/plain over HTML
+func wantsText(r *http.Request) bool {
+	agent := strings.ToLower(r.UserAgent())
+	for _, prefix := range terminalAgents {
+		if strings.HasPrefix(agent, prefix) {
+			return true
+		}
+	}
+	accept := r.Header.Get("Accept")
+	return mediaTypeQuality(accept, "text/plain") > mediaTypeQuality(accept, "text/html")
+}
+
+// mediaTypeQuality returns the q-value the Accept header gives mediaType, taken from the
+// most specific range matching it: "text/plain" over "text/*" over "*/*"
+func mediaTypeQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		rangeSpecificity := -1
+		switch name {
+		case mediaType:
+			rangeSpecificity = 2
+		case typ + "/*":
+			rangeSpecificity = 1
+		case "*/*":
+			rangeSpecificity = 0
+		}
+		if rangeSpecificity <= specificity {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 && parsed <= 1 {
+					q = parsed
+				} else {
+					q = 0
+				}
+			}
+		}
+		quality, specificity = q, rangeSpecificity
+	}
+	return quality
+}
+
+type textWriter struct {
+	w     io.Writer
+	width int
+	color bool
+}
+
+func (t *textWriter) style(code, s string) string {
+	if !t.color || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// wrap word-wraps text to the writer's width, prefixing the first line with first
+// and the following ones with as many spaces
+func (t *textWriter) wrap(first, text string) {
+	indent := strings.Repeat(" ", utf8.RuneCountInString(first))
+	line, lineLen := first, utf8.RuneCountInString(first)
+	empty := true
+	for _, word := range strings.Fields(text) {
+		wordLen := utf8.RuneCountInString(word)
+		if !empty && lineLen+1+wordLen > t.width {
+			fmt.Fprintln(t.w, line)
+			line, lineLen, empty = indent, len(indent), true
+		}
+		if !empty {
+			line += " "
+			lineLen++
+		}
+		line += word
+		lineLen += wordLen
+		empty = false
+	}
+	fmt.Fprintln(t.w, line)
+}
+
+func (t *textWriter) heading(title string) {
+	title = strings.ToUpper(title)
+	rule := t.width - utf8.RuneCountInString(title) - 4
+	if rule < 0 {
+		rule = 0
+	}
+	fmt.Fprintf(t.w, "\n%s %s %s\n\n", t.style("35", "──"), t.style("1;35", title), t.style("35", strings.Repeat("─", rule)))
+}
+
+func (t *textWriter) experienceItem(item templates.ExperienceItem, expanded bool, lang string) {
+	fmt.Fprintf(t.w, "%s %s %s\n", t.style("1", item.Title), t.style("2", "·"), t.style("33", item.Company))
+	fmt.Fprintln(t.w, t.style("2", strings.Join(nonEmpty(item.PeriodLabel(lang), item.Duration(lang), item.Location), " · ")))
+	t.wrap("  ", templates.PlainText(item.Summary))
+	if expanded {
+		for _, desc := range item.Description {
+			t.wrap("    • ", desc.String())
+		}
+	}
+	fmt.Fprintln(t.w)
+}
+
+func (t *textWriter) education(items []templates.EducationItem, lang string) {
+	for _, item := range items {
+		fmt.Fprintf(t.w, "%s %s %s\n", t.style("1", item.Title), t.style("2", "·"), t.style("33", item.Institution))
+		fmt.Fprintln(t.w, t.style("2", strings.Join(nonEmpty(item.PeriodLabel(lang), item.Qualification), " · ")))
+		fmt.Fprintln(t.w)
+	}
+}
+
+func nonEmpty(values ...string) []string {
+	kept := []string{}
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
+func (t *textWriter) projects(items []templates.ProjectItem) {
+	for _, item := range items {
+		fmt.Fprintln(t.w, t.style("1", item.Title))
+		t.wrap("  ", templates.PlainText(item.Description))
+		fmt.Fprintf(t.w, "  %s\n\n", t.style("4;34", item.GitHubLink))
+	}
+}
+
+func (t *textWriter) skills(data templates.SkillsData, lang string) {
+	for _, category := range data.Categories {
+		names := []string{}
+		for _, skill := range category.Skills {
+			label := templates.GetTranslation("level_"+skill.Level, lang)
+			if skill.Years > 0 {
+				label += ", " + templates.Plural(lang, "years_of_experience", skill.Years)
+			}
+			names = append(names, fmt.Sprintf("%s (%s)", skill.Name, label))
+		}
+		fmt.Fprintln(t.w, t.style("1", category.Name))
+		t.wrap("  ", strings.Join(names, " · "))
+		fmt.Fprintln(t.w)
+	}
+}
+
+// serveTextCV writes data as ANSI-colored text; ?width= sets the line width,
+// ?color=0 disables colors and ?expand=all (or a slug) adds description bullets
+func serveTextCV(w http.ResponseWriter, r *http.Request, data templates.IndexData) {
+	width, err := strconv.Atoi(r.URL.Query().Get("width"))
+	if err != nil {
+		width = defaultTextWidth
+	}
+	width = max(minTextWidth, min(width, maxTextWidth))
+	t := &textWriter{w: w, width: width, color: r.URL.Query().Get("color") != "0"}
+	expand := r.URL.Query().Get("expand")
+	lang := data.Language
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Add("Vary", "User-Agent, Accept")
+
+	profile := data.Profile
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, t.style("1;36", profile.Name))
+	fmt.Fprintln(w, t.style("36", profile.JobTitle))
+	for _, link := range profile.Links {
+		fmt.Fprintf(w, "%s %s\n", t.style("2", link.Name+":"), link.URL)
+	}
+	fmt.Fprintln(w)
+	t.wrap("", templates.PlainText(profile.Text))
+
+	t.heading(templates.GetTranslation("professional_experience", lang))
+	for _, item := range data.Experience.ExperienceItems {
+		t.experienceItem(item, expand == "all" || expand == item.Slug, lang)
+	}
+	t.heading(templates.GetTranslation("education", lang))
+	t.education(data.Education.EducationItems, lang)
+	t.heading(templates.GetTranslation("personal_projects", lang))
+	t.projects(data.Projects.ProjectItems)
+	t.heading(templates.GetTranslation("skills", lang))
+	t.skills(data.Skills, lang)
+	fmt.Fprintln(w)
+	t.wrap("", templates.GetTranslation("terminal_hint", lang))
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWantsText(t *testing.T) {
+	for _, tc := range []struct {
+		agent, accept string
+		want          bool
+	}{
+		{"curl/8.5.0", "*/*", true},
+		{"Mozilla/5.0", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"Mozilla/5.0", "", false},
+		{"Mozilla/5.0", "*/*", false},
+		{"Mozilla/5.0", "text/*", false},
+		{"Mozilla/5.0", "text/plain", true},
+		{"Mozilla/5.0", "text/plain, text/html;q=0.1", true},
+		{"Mozilla/5.0", "text/plain;q=0.5, text/html", false},
+		{"Mozilla/5.0", "text/plain, text/html", false},
+		{"Mozilla/5.0", "text/plain, */*;q=0.1", true},
+		{"Mozilla/5.0", "text/*;q=0.2, text/plain", true},
+		{"Mozilla/5.0", "text/plain;q=0, */*", false},
+		{"Mozilla/5.0", "TEXT/PLAIN; Q=0.9, text/html; q=0.8", true},
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("User-Agent", tc.agent)
+		if tc.accept != "" {
+			r.Header.Set("Accept", tc.accept)
+		}
+		if got := wantsText(r); got != tc.want {
+			t.Errorf("wantsText(%q, Accept: %q) = %v, want %v", tc.agent, tc.accept, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"log"
+
+	"testserver/templates"
+)
+
+// Third-party frontend dependencies are vendored into static/vendor and embedded in the
+// binary. animate.min.css holds only the animations the templates use; the others are
+// refreshed by go generate.
+//go:generate curl -sSfL --create-dirs -o static/vendor/htmx.min.js https://unpkg.com/htmx.org@1.9.10/dist/htmx.min.js
+//go:generate npx --yes tailwindcss@3.4.17 -c tailwind.config.js -i tailwind.input.css -o static/vendor/tailwind.min.css --minify
+
+// CDN copies used with -cdn, pinned to the SRI hashes their CDNs publish. The compiled
+// Tailwind stylesheet has no CDN counterpart and is always self-hosted.
+var vendorCDN = map[string]templates.VendorAsset{
+	"htmx.min.js": {
+		URL:       "https://unpkg.com/htmx.org@1.9.10/dist/htmx.min.js",
+		Integrity: "sha384-D1Kt99CQMDuVetoL1lrYwg5t+9QdHe7NLX/SoJYkXDFfX37iInKRy5xLSi8nO7UC",
+	},
+	"animate.min.css": {
+		URL:       "https://cdnjs.cloudflare.com/ajax/libs/animate.css/4.1.1/animate.min.css",
+		Integrity: "sha512-c42qTSw/wPZ3/5LBzD+Bw5f7bSF2oxou6wEb+I/lqeaKV5FDIfMvvRp772y4jcJLKuGUOpbJMdg/BTl50fJYAw==",
+	},
+}
+
+var vendorFiles = []string{"htmx.min.js", "animate.min.css", "tailwind.min.css"}
+
+// subresourceIntegrity returns the SRI hash of content
+func subresourceIntegrity(content []byte) string {
+	sum := sha512.Sum384(content)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// vendorAssets resolves each third-party dependency to its self-hosted URL, or to its
+// CDN URL with useCDN
+func vendorAssets(store *assetStore, useCDN bool) map[string]templates.VendorAsset {
+	deps := map[string]templates.VendorAsset{}
+	for _, name := range vendorFiles {
+		if cdn, ok := vendorCDN[name]; ok && useCDN {
+			deps[name] = cdn
+			continue
+		}
+		asset, ok := store.byName["vendor/"+name]
+		if !ok {
+			log.Fatalf("static/vendor/%s is missing from the build", name)
+		}
+		deps[name] = templates.VendorAsset{URL: "/static/" + asset.hashedName, Integrity: subresourceIntegrity(asset.content)}
+	}
+	return deps
+}
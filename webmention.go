@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/net/html"
+	"testserver/templates"
+)
+
+// Moderation states of a verified webmention
+const (
+	mentionPending  = "pending"
+	mentionApproved = "approved"
+	mentionRejected = "rejected"
+)
+
+const (
+	maxMentionSourceSize = 1 << 20
+	mentionQueueSize     = 64
+)
+
+type webmention struct {
+	ID       string    `json:"id"`
+	Source   string    `json:"source"`
+	Target   string    `json:"target"`
+	Slug     string    `json:"slug"` // Project the target points at, empty for the page itself
+	Title    string    `json:"title"`
+	Status   string    `json:"status"`
+	Verified time.Time `json:"verified"`
+}
+
+type mentionRequest struct {
+	source, target, slug string
+}
+
+// webmentionStore receives webmentions, verifies them in the background and keeps
+// verified ones in a JSON file until they are moderated.
+type webmentionStore struct {
+	path   string
+	client *http.Client
+	queue  chan mentionRequest
+
+	mu       sync.Mutex
+	mentions map[string]*webmention
+}
+
+func newWebmentionStore(path string) (*webmentionStore, error) {
+	store := &webmentionStore{
+		path:     path,
+		client:   newPublicClient(10 * time.Second),
+		queue:    make(chan mentionRequest, mentionQueueSize),
+		mentions: map[string]*webmention{},
+	}
+	content, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		var mentions []*webmention
+		if err := json.Unmarshal(content, &mentions); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, m := range mentions {
+			store.mentions[m.ID] = m
+		}
+	}
+	go store.verifyQueued()
+	return store, nil
+}
+
+func mentionID(source, target string) string {
+	sum := sha256.Sum256([]byte(source + "\n" + target))
+	return hex.EncodeToString(sum[:8])
+}
+
+// mentionTarget checks that target is a page on this site and returns the project
+// slug it refers to through a #project-<slug> fragment
+func mentionTarget(base string, target *url.URL) (string, bool) {
+	site, err := url.Parse(base)
+	if err != nil || !strings.EqualFold(target.Host, site.Host) || target.Path != "/" && target.Path != "" {
+		return "", false
+	}
+	slug, ok := strings.CutPrefix(target.Fragment, "project-")
+	if !ok {
+		return "", target.Fragment == ""
+	}
+	for _, item := range loadProjectsData("en").ProjectItems {
+		if item.Slug == slug {
+			return slug, true
+		}
+	}
+	return "", false
+}
+
+// ServeHTTP accepts a Webmention notification and queues it for verification
+func (s *webmentionStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	source, errSource := url.Parse(r.FormValue("source"))
+	target, errTarget := url.Parse(r.FormValue("target"))
+	if errSource != nil || errTarget != nil || source.Host == "" || target.Host == "" ||
+		(source.Scheme != "http" && source.Scheme != "https") ||
+		(target.Scheme != "http" && target.Scheme != "https") {
+		http.Error(w, "source and target must be http(s) URLs", http.StatusBadRequest)
+		return
+	}
+	if source.String() == target.String() {
+		http.Error(w, "source and target must differ", http.StatusBadRequest)
+		return
+	}
+	slug, ok := mentionTarget(baseURL(r), target)
+	if !ok {
+		http.Error(w, "target is not a page on this site", http.StatusBadRequest)
+		return
+	}
+	select {
+	case s.queue <- mentionRequest{source: source.String(), target: target.String(), slug: slug}:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "Too many pending webmentions, try again later", http.StatusServiceUnavailable)
+	}
+}
+
+func (s *webmentionStore) verifyQueued() {
+	for req := range s.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		title, err := s.verify(ctx, req.source, req.target)
+		cancel()
+		id := mentionID(req.source, req.target)
+
+		s.mu.Lock()
+		existing := s.mentions[id]
+		switch {
+		case err != nil && existing != nil:
+			// The source no longer links to us: the mention is withdrawn
+			delete(s.mentions, id)
+		case err != nil:
+		case existing != nil:
+			existing.Title, existing.Verified = title, time.Now()
+		default:
+			s.mentions[id] = &webmention{
+				ID:       id,
+				Source:   req.source,
+				Target:   req.target,
+				Slug:     req.slug,
+				Title:    title,
+				Status:   mentionPending,
+				Verified: time.Now(),
+			}
+		}
+		if err != nil {
+			log.Printf("Webmention from %s not verified: %v", req.source, err)
+		}
+		s.saveLocked()
+		s.mu.Unlock()
+	}
+}
+
+// verify fetches source and checks that it links to target, returning the source's
+// title; the client refuses loopback, link-local and private addresses
+func (s *webmentionStore) verify(ctx context.Context, source, target string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/html")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching source: %s", resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" {
+		return "", fmt.Errorf("source is %q, not HTML", mediaType)
+	}
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxMentionSourceSize))
+	if err != nil {
+		return "", err
+	}
+	sourceURL := resp.Request.URL // After redirects
+	title, found := "", false
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if title == "" && n.FirstChild != nil {
+					title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "a", "link", "img", "video", "audio":
+				for _, attr := range n.Attr {
+					if attr.Key != "href" && attr.Key != "src" {
+						continue
+					}
+					if ref, err := sourceURL.Parse(attr.Val); err == nil && ref.String() == target {
+						found = true
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	if !found {
+		return "", errors.New("source does not link to target")
+	}
+	return title, nil
+}
+
+// saveLocked writes the mentions file; callers hold mu
+func (s *webmentionStore) saveLocked() {
+	mentions := make([]*webmention, 0, len(s.mentions))
+	for _, m := range s.mentions {
+		mentions = append(mentions, m)
+	}
+	sort.Slice(mentions, func(i, j int) bool { return mentions[i].Verified.Before(mentions[j].Verified) })
+	content, err := json.MarshalIndent(mentions, "", "\t")
+	if err == nil {
+		err = os.WriteFile(s.path, content, 0600)
+	}
+	if err != nil {
+		log.Printf("Error saving webmentions: %v", err)
+	}
+}
+
+// Approved returns approved mentions grouped by project slug, oldest first
+func (s *webmentionStore) Approved() map[string][]templates.Mention {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var approved []*webmention
+	for _, m := range s.mentions {
+		if m.Status == mentionApproved && m.Slug != "" {
+			approved = append(approved, m)
+		}
+	}
+	sort.Slice(approved, func(i, j int) bool { return approved[i].Verified.Before(approved[j].Verified) })
+	bySlug := map[string][]templates.Mention{}
+	for _, m := range approved {
+		bySlug[m.Slug] = append(bySlug[m.Slug], templates.Mention{Source: m.Source, Title: m.Title})
+	}
+	return bySlug
+}
+
+// authorized checks the bearer token from WEBMENTION_ADMIN_TOKEN; moderation is disabled without it
+func authorized(r *http.Request) bool {
+	token := os.Getenv("WEBMENTION_ADMIN_TOKEN")
+	return token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+// Routes mounts the moderation endpoints on router
+func (s *webmentionStore) Routes(router chi.Router) {
+	router.Get("/admin/webmentions", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		s.mu.Lock()
+		mentions := make([]webmention, 0, len(s.mentions))
+		for _, m := range s.mentions {
+			mentions = append(mentions, *m)
+		}
+		s.mu.Unlock()
+		sort.Slice(mentions, func(i, j int) bool { return mentions[i].Verified.Before(mentions[j].Verified) })
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mentions)
+	})
+
+	router.Post("/admin/webmentions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		status := r.FormValue("status")
+		if status != mentionApproved && status != mentionRejected && status != mentionPending {
+			http.Error(w, "status must be approved, rejected or pending", http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		m, ok := s.mentions[chi.URLParam(r, "id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		m.Status = status
+		s.saveLocked()
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newTestMentions returns a store whose sources are served by source, on loopback
+func newTestMentions(t *testing.T, source *httptest.Server) *webmentionStore {
+	t.Helper()
+	store, err := newWebmentionStore(filepath.Join(t.TempDir(), "webmentions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.client = source.Client()
+	return store
+}
+
+func sourcePage(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(body))
+	}
+}
+
+func postMention(store *webmentionStore, source, target string) *httptest.ResponseRecorder {
+	form := url.Values{"source": {source}, "target": {target}}
+	req := httptest.NewRequest(http.MethodPost, "http://cv.example/webmention", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	store.ServeHTTP(rec, req)
+	return rec
+}
+
+// waitForMention waits for the background verification of source and target
+func waitForMention(t *testing.T, store *webmentionStore, source, target string) *webmention {
+	t.Helper()
+	id := mentionID(source, target)
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		store.mu.Lock()
+		m, ok := store.mentions[id]
+		store.mu.Unlock()
+		if ok {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestWebmentionVerifiedAndModerated(t *testing.T) {
+	slug := loadProjectsData("en").ProjectItems[0].Slug
+	target := "http://cv.example/#project-" + slug
+	source := httptest.NewServer(sourcePage(`<html><head><title>A post about it</title></head>
+<body><p>Look at <a href="` + target + `">this project</a>.</p></body></html>`))
+	defer source.Close()
+	store := newTestMentions(t, source)
+
+	if rec := postMention(store, source.URL+"/post", target); rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /webmention got %d: %s", rec.Code, rec.Body)
+	}
+	m := waitForMention(t, store, source.URL+"/post", target)
+	if m == nil {
+		t.Fatal("mention was not verified")
+	}
+	if m.Status != mentionPending || m.Slug != slug || m.Title != "A post about it" {
+		t.Errorf("mention = %+v", m)
+	}
+	if len(store.Approved()[slug]) != 0 {
+		t.Error("pending mention is shown")
+	}
+
+	router := chi.NewRouter()
+	store.Routes(router)
+	t.Setenv("WEBMENTION_ADMIN_TOKEN", "secret")
+	moderate := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/webmentions/"+m.ID, strings.NewReader("status=approved"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := moderate("secre"); code != http.StatusUnauthorized {
+		t.Errorf("wrong token got %d", code)
+	}
+	if code := moderate("secret"); code != http.StatusNoContent {
+		t.Fatalf("approving got %d", code)
+	}
+	if got := store.Approved()[slug]; len(got) != 1 || got[0].Title != "A post about it" {
+		t.Errorf("approved mentions = %v", got)
+	}
+}
+
+func TestWebmentionWithoutLink(t *testing.T) {
+	target := "http://cv.example/"
+	source := httptest.NewServer(sourcePage(`<html><body><a href="http://cv.example/other">elsewhere</a></body></html>`))
+	defer source.Close()
+	store := newTestMentions(t, source)
+
+	if _, err := store.verify(t.Context(), source.URL, target); err == nil {
+		t.Error("source without a link to the target was verified")
+	}
+}
+
+func TestWebmentionRefusesPrivateSources(t *testing.T) {
+	store, err := newWebmentionStore(filepath.Join(t.TempDir(), "webmentions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("verification reached a loopback server")
+	}))
+	defer source.Close()
+	_, err = store.verify(t.Context(), source.URL, "http://cv.example/")
+	if err == nil || !strings.Contains(err.Error(), "non-public address") {
+		t.Errorf("verifying a loopback source: %v, want a refusal", err)
+	}
+}